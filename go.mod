@@ -3,11 +3,11 @@ module github.com/jdkato/twine
 go 1.20
 
 require (
+	github.com/errata-ai/regexp2 v1.7.0
 	github.com/montanaflynn/stats v0.7.1
+	golang.org/x/text v0.14.0
 	gopkg.in/neurosnap/sentences.v1 v1.0.7
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/errata-ai/regexp2 v1.7.0 // indirect
-	github.com/neurosnap/sentences v1.1.2 // indirect
-)
+require github.com/neurosnap/sentences v1.1.2 // indirect
@@ -0,0 +1,273 @@
+/*
+Package collocation implements functions for finding collocations: sequences
+of words, such as "pull request" or "access token", that occur together more
+often than their individual frequencies would suggest.
+
+A Finder counts the bigrams or trigrams of a sequence of sentences, which
+must already be tokenized, and then ranks them by one of several measures of
+association:
+
+	f := collocation.NewBigramFinder(collocation.UsingMinFrequency(3))
+	for _, sentence := range sentences {
+		f.Add(sentence)
+	}
+	top := f.Score(collocation.LikelihoodRatio)
+
+The measures follow those of NLTK's nltk.metrics.association module.
+*/
+package collocation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jdkato/twine/nlp/ngram"
+)
+
+// small prevents division by zero.
+const small = 1e-20
+
+// A Measure is a measure of association between the words of an n-gram.
+type Measure int
+
+const (
+	// PMI is pointwise mutual information, which favors rare n-grams.
+	PMI Measure = iota
+	// LikelihoodRatio is Dunning's log-likelihood ratio.
+	LikelihoodRatio
+	// ChiSquare is Pearson's chi-square statistic.
+	ChiSquare
+	// StudentT is Student's t-score, which favors frequent n-grams.
+	StudentT
+)
+
+// A Collocation is a scored n-gram.
+type Collocation struct {
+	NGram ngram.NGram
+	Count int     // the number of times the n-gram occurred
+	Score float64 // the n-gram's score according to a Measure
+}
+
+// A Finder finds collocations of a fixed length (2 or 3 words).
+type Finder struct {
+	n         int
+	minFreq   int
+	isIgnored func(string) bool
+
+	total    int
+	unigrams *ngram.Counter
+	ngrams   *ngram.Counter
+	// partial counts the pairs of words that are part of a trigram: its
+	// bigrams and its first and last words (as "w1 w3").
+	partial map[int]*ngram.Counter
+}
+
+// FinderOptFunc configures a Finder.
+type FinderOptFunc func(*Finder)
+
+// UsingMinFrequency omits n-grams that occur fewer than freq times.
+func UsingMinFrequency(freq int) FinderOptFunc {
+	return func(f *Finder) {
+		f.minFreq = freq
+	}
+}
+
+// UsingWordFilter omits n-grams that contain a word for which isIgnored
+// returns true (e.g., summarize.IsStopWord).
+func UsingWordFilter(isIgnored func(string) bool) FinderOptFunc {
+	return func(f *Finder) {
+		f.isIgnored = isIgnored
+	}
+}
+
+// NewBigramFinder creates a Finder for two-word collocations.
+func NewBigramFinder(opts ...FinderOptFunc) *Finder {
+	return newFinder(2, opts)
+}
+
+// NewTrigramFinder creates a Finder for three-word collocations.
+func NewTrigramFinder(opts ...FinderOptFunc) *Finder {
+	return newFinder(3, opts)
+}
+
+func newFinder(n int, opts []FinderOptFunc) *Finder {
+	f := &Finder{
+		n:        n,
+		minFreq:  1,
+		unigrams: ngram.NewCounter(),
+		ngrams:   ngram.NewCounter(),
+		partial:  make(map[int]*ngram.Counter),
+	}
+	for _, applyOpt := range opts {
+		applyOpt(f)
+	}
+	return f
+}
+
+// Add counts the words and n-grams of a sentence. N-grams never span
+// sentences.
+func (f *Finder) Add(words []string) {
+	f.total += len(words)
+	for _, word := range words {
+		f.unigrams.Add(ngram.NGram{word})
+	}
+
+	generator := ngram.NewGenerator()
+	f.ngrams.Add(generator.NGrams(words, f.n)...)
+	if f.n == 3 {
+		for gap := 0; gap <= 1; gap++ {
+			if f.partial[gap] == nil {
+				f.partial[gap] = ngram.NewCounter()
+			}
+			for i := 0; i+gap+1 < len(words); i++ {
+				f.partial[gap].Add(ngram.NGram{words[i], words[i+gap+1]})
+			}
+		}
+	}
+}
+
+// Score returns the Finder's n-grams ranked by measure, in descending order
+// of their scores.
+//
+// Score panics if measure isn't one of the Measures defined by this package.
+func (f *Finder) Score(measure Measure) []Collocation {
+	switch measure {
+	case PMI, LikelihoodRatio, ChiSquare, StudentT:
+	default:
+		panic(fmt.Sprintf("collocation: unknown Measure %d", measure))
+	}
+
+	var scored []Collocation
+	for _, freq := range f.ngrams.Top(-1) {
+		if freq.Count < f.minFreq || f.ignores(freq.NGram) {
+			continue
+		}
+		scored = append(scored, Collocation{
+			NGram: freq.NGram,
+			Count: freq.Count,
+			Score: f.score(measure, freq.NGram),
+		})
+	}
+
+	// The n-grams are already ordered by frequency, which breaks ties.
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+
+	return scored
+}
+
+// ignores reports whether gram contains an ignored word.
+func (f *Finder) ignores(gram ngram.NGram) bool {
+	if f.isIgnored == nil {
+		return false
+	}
+	for _, word := range gram {
+		if f.isIgnored(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// marginal returns the number of n-grams whose words at the positions in
+// mask (a bit set) match those of gram, while the others may be any word.
+func (f *Finder) marginal(gram ngram.NGram, mask int) float64 {
+	var positions []int
+	for i := 0; i < f.n; i++ {
+		if mask&(1<<i) != 0 {
+			positions = append(positions, i)
+		}
+	}
+
+	switch len(positions) {
+	case 0:
+		return float64(f.total)
+	case 1:
+		return float64(f.unigrams.Count(ngram.NGram{gram[positions[0]]}))
+	case f.n:
+		return float64(f.ngrams.Count(gram))
+	}
+
+	// A pair of words from a trigram.
+	i, j := positions[0], positions[1]
+	return float64(f.partial[j-i-1].Count(ngram.NGram{gram[i], gram[j]}))
+}
+
+// score computes measure from gram's contingency table.
+func (f *Finder) score(measure Measure, gram ngram.NGram) float64 {
+	cells := 1 << f.n
+	full := cells - 1
+
+	marginals := make([]float64, cells)
+	for mask := range marginals {
+		marginals[mask] = f.marginal(gram, mask)
+	}
+	total := marginals[0]
+
+	// product is the product of the words' individual counts.
+	product := 1.0
+	for i := 0; i < f.n; i++ {
+		product *= marginals[1<<i]
+	}
+	scale := math.Pow(total, float64(f.n-1))
+
+	switch measure {
+	case PMI:
+		return math.Log2(marginals[full]*scale) - math.Log2(product)
+	case StudentT:
+		return (marginals[full] - product/scale) / (math.Sqrt(marginals[full]) + small)
+	}
+
+	// The observed count of each cell is the number of n-grams that match
+	// gram in exactly the positions of its mask, which follows from the
+	// marginals by inclusion-exclusion. The expected count assumes that the
+	// words are independent.
+	result := 0.0
+	for mask := 0; mask < cells; mask++ {
+		observed := 0.0
+		for super := mask; super < cells; super = (super + 1) | mask {
+			if bitCount(super^mask)%2 == 0 {
+				observed += marginals[super]
+			} else {
+				observed -= marginals[super]
+			}
+		}
+
+		expected := 1.0
+		for i := 0; i < f.n; i++ {
+			if mask&(1<<i) != 0 {
+				expected *= marginals[1<<i]
+			} else {
+				expected *= total - marginals[1<<i]
+			}
+		}
+		expected /= scale
+
+		switch measure {
+		case LikelihoodRatio:
+			if observed > 0 {
+				result += 2 * observed * math.Log(observed/(expected+small)+small)
+			}
+		case ChiSquare:
+			result += (observed - expected) * (observed - expected) / (expected + small)
+		}
+	}
+
+	return result
+}
+
+func bitCount(x int) int {
+	count := 0
+	for ; x != 0; x &= x - 1 {
+		count++
+	}
+	return count
+}
+
+// String returns the Collocation's words separated by spaces.
+func (c Collocation) String() string {
+	return strings.Join(c.NGram, " ")
+}
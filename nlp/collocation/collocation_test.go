@@ -0,0 +1,80 @@
+package collocation_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jdkato/twine/nlp/collocation"
+)
+
+var sentences = []string{
+	"open a pull request to merge",
+	"the pull request was merged",
+	"a new access token",
+	"revoke the access token now",
+	"the token was new",
+	"merge the request",
+}
+
+func find(f *collocation.Finder) *collocation.Finder {
+	for _, s := range sentences {
+		f.Add(strings.Fields(s))
+	}
+	return f
+}
+
+// The expected scores were computed with NLTK's BigramAssocMeasures and
+// TrigramAssocMeasures.
+func TestScore(t *testing.T) {
+	cases := []struct {
+		finder   *collocation.Finder
+		measure  collocation.Measure
+		expected string
+		score    float64
+	}{
+		{find(collocation.NewBigramFinder()), collocation.PMI, "open a", 3.754888},
+		{find(collocation.NewBigramFinder()), collocation.LikelihoodRatio, "access token", 10.439726},
+		{find(collocation.NewBigramFinder()), collocation.ChiSquare, "access token", 17.28},
+		{find(collocation.NewBigramFinder()), collocation.StudentT, "access token", 1.257079},
+		{find(collocation.NewTrigramFinder()), collocation.PMI, "open a pull", 7.509775},
+		{find(collocation.NewTrigramFinder()), collocation.LikelihoodRatio, "access token now", 16.221308},
+		{find(collocation.NewTrigramFinder()), collocation.ChiSquare, "open a pull", 182.211785},
+		{find(collocation.NewTrigramFinder()), collocation.StudentT, "open a pull", 0.994513},
+	}
+
+	for _, c := range cases {
+		top := c.finder.Score(c.measure)[0]
+		if top.String() != c.expected || math.Abs(top.Score-c.score) > 1e-6 {
+			t.Errorf("%d: expected '%s' (%f), got '%s' (%f)",
+				c.measure, c.expected, c.score, top, top.Score)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	stop := map[string]bool{"a": true, "the": true, "to": true, "was": true}
+	f := find(collocation.NewBigramFinder(
+		collocation.UsingMinFrequency(2),
+		collocation.UsingWordFilter(func(w string) bool { return stop[w] }),
+	))
+
+	top := f.Score(collocation.PMI)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 collocations, got %v", top)
+	}
+	for i, expected := range []string{"access token", "pull request"} {
+		if top[i].String() != expected || top[i].Count != 2 {
+			t.Errorf("expected '%s' (2), got '%s' (%d)", expected, top[i], top[i].Count)
+		}
+	}
+}
+
+func TestUnknownMeasure(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an unknown Measure")
+		}
+	}()
+	collocation.NewBigramFinder().Score(collocation.Measure(-1))
+}
@@ -0,0 +1,236 @@
+/*
+Package lemma implements a part-of-speech-aware lemmatizer for English.
+
+A Lemmatizer reduces a tagged word to its lemma (e.g., "ran/VBD" becomes
+"run" and "mice/NNS" becomes "mouse") in the style of WordNet's morphy: the
+word is first looked up in a list of exceptions for its part of speech and,
+if it isn't found, its inflectional suffix is removed according to a set of
+rules.
+*/
+package lemma
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"strings"
+	"sync"
+
+	"github.com/jdkato/twine/nlp/tag"
+)
+
+// The built-in exception lists use the format of WordNet's exception files:
+// each line holds an inflected form followed by its lemma.
+
+//go:embed noun.exc
+var encodedNounExceptions []byte
+
+//go:embed verb.exc
+var encodedVerbExceptions []byte
+
+//go:embed adj.exc
+var encodedAdjectiveExceptions []byte
+
+//go:embed adv.exc
+var encodedAdverbExceptions []byte
+
+var builtinExceptions map[POS]map[string]string
+var loadExceptions sync.Once
+
+// A POS is a part of speech that has inflected forms.
+type POS int
+
+const (
+	// Other covers the parts of speech that aren't inflected.
+	Other POS = iota
+	// Noun covers common and proper nouns.
+	Noun
+	// Verb covers verbs, but not modals.
+	Verb
+	// Adjective covers adjectives, including comparatives and superlatives.
+	Adjective
+	// Adverb covers adverbs, including comparatives and superlatives.
+	Adverb
+)
+
+// PartOfSpeech returns the POS of a Penn Treebank tag.
+func PartOfSpeech(tag string) POS {
+	switch {
+	case strings.HasPrefix(tag, "NN"):
+		return Noun
+	case strings.HasPrefix(tag, "VB"):
+		return Verb
+	case strings.HasPrefix(tag, "JJ"):
+		return Adjective
+	case strings.HasPrefix(tag, "RB"):
+		return Adverb
+	}
+	return Other
+}
+
+// presentForms are the present-tense forms of verbs that differ from their
+// base forms (i.e., those tagged as VBP rather than VBZ).
+var presentForms = map[string]string{
+	"am": "be", "are": "be", "'m": "be", "'re": "be", "ai": "be",
+	"'ve": "have",
+}
+
+// modals are the lemmas of contracted modals.
+var modals = map[string]string{
+	"ca": "can", "wo": "will", "'ll": "will", "'d": "would", "sha": "shall",
+}
+
+// Lemmatizer reduces words to their lemmas according to their Penn Treebank
+// tags.
+type Lemmatizer struct {
+	exceptions map[POS]map[string]string
+}
+
+// LemmatizerOptFunc configures a Lemmatizer.
+type LemmatizerOptFunc func(*Lemmatizer)
+
+// UsingExceptions adds exceptions, a map of inflected forms to their lemmas,
+// for the given part of speech. Exceptions take precedence over the built-in
+// lists and, unlike them, also apply to base forms (e.g., words tagged as VB
+// or NN); their lemmas are returned exactly as given.
+func UsingExceptions(pos POS, exceptions map[string]string) LemmatizerOptFunc {
+	return func(l *Lemmatizer) {
+		if l.exceptions[pos] == nil {
+			l.exceptions[pos] = make(map[string]string)
+		}
+		for word, lemma := range exceptions {
+			l.exceptions[pos][strings.ToLower(word)] = lemma
+		}
+	}
+}
+
+// NewLemmatizer is a Lemmatizer constructor.
+func NewLemmatizer(opts ...LemmatizerOptFunc) *Lemmatizer {
+	loadExceptions.Do(func() {
+		builtinExceptions = map[POS]map[string]string{
+			Noun:      readExceptions(encodedNounExceptions),
+			Verb:      readExceptions(encodedVerbExceptions),
+			Adjective: readExceptions(encodedAdjectiveExceptions),
+			Adverb:    readExceptions(encodedAdverbExceptions),
+		}
+	})
+
+	l := &Lemmatizer{exceptions: make(map[POS]map[string]string)}
+	for _, applyOpt := range opts {
+		applyOpt(l)
+	}
+
+	return l
+}
+
+// Lemmatize returns the lemma of each of tokens.
+func (l *Lemmatizer) Lemmatize(tokens []tag.Token) []string {
+	lemmas := make([]string, len(tokens))
+	for i, tok := range tokens {
+		lemmas[i] = l.Lemma(tok.Text, tok.Tag)
+	}
+	return lemmas
+}
+
+// Lemma returns the lemma of word, which has the given Penn Treebank tag.
+//
+// Lemmas are lowercase, except for those of proper nouns, which keep their
+// case. Words whose tags aren't inflected forms (e.g., NN or VB) are
+// returned as they are, apart from being lowercased.
+func (l *Lemmatizer) Lemma(word, tag string) string {
+	lower := strings.ToLower(word)
+	pos := PartOfSpeech(tag)
+
+	if lemma, found := l.exceptions[pos][lower]; found {
+		return lemma
+	}
+
+	switch tag {
+	case "NNP":
+		return word
+	case "NNS", "NNPS":
+		return keepCase(word, lower, inflected(Noun, lower, nounLemma), tag)
+	case "VBD", "VBN":
+		return inflected(Verb, lower, func(w string) string {
+			return verbLemma(w, "ed")
+		})
+	case "VBG":
+		return inflected(Verb, lower, func(w string) string {
+			return verbLemma(w, "ing")
+		})
+	case "VBZ":
+		return inflected(Verb, lower, thirdPersonLemma)
+	case "VB", "VBP":
+		if lemma, found := presentForms[lower]; found {
+			return lemma
+		}
+	case "JJR", "RBR":
+		return inflected(pos, lower, func(w string) string {
+			return comparativeLemma(w, "er")
+		})
+	case "JJS", "RBS":
+		return inflected(pos, lower, func(w string) string {
+			return comparativeLemma(w, "est")
+		})
+	case "RB":
+		if lemma, found := builtinExceptions[Adverb][lower]; found {
+			return lemma
+		}
+	case "MD":
+		if lemma, found := modals[lower]; found {
+			return lemma
+		}
+	}
+
+	return lower
+}
+
+// inflected returns the lemma of an inflected word: its built-in exception,
+// if it has one, or the result of applying rules to it otherwise.
+func inflected(pos POS, word string, rules func(string) string) string {
+	if lemma, found := builtinExceptions[pos][word]; found {
+		return lemma
+	} else if !isRegular(word) {
+		return word
+	}
+	return rules(word)
+}
+
+// keepCase restores the case of a proper noun's lemma, which was computed
+// from its lowercase form.
+func keepCase(word, lower, lemma, tag string) string {
+	if !strings.HasPrefix(tag, "NNP") || len(word) != len(lower) {
+		return lemma
+	}
+	i := 0
+	for i < len(lemma) && i < len(lower) && lemma[i] == lower[i] {
+		i++
+	}
+	return word[:i] + lemma[i:]
+}
+
+// isRegular reports whether the suffix rules apply to word: it must consist
+// of ASCII letters (and, after the first letter, hyphens and apostrophes).
+func isRegular(word string) bool {
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if (c < 'a' || c > 'z') && (i == 0 || (c != '-' && c != '\'')) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+func readExceptions(data []byte) map[string]string {
+	exceptions := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 {
+			exceptions[fields[0]] = fields[1]
+		}
+	}
+
+	return exceptions
+}
@@ -0,0 +1,180 @@
+package lemma_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jdkato/twine/nlp/lemma"
+	"github.com/jdkato/twine/nlp/tag"
+)
+
+var lemmatizer = lemma.NewLemmatizer()
+
+func TestLemma(t *testing.T) {
+	cases := []struct {
+		word, tag, lemma string
+	}{
+		// Irregular forms.
+		{"ran", "VBD", "run"},
+		{"was", "VBD", "be"},
+		{"been", "VBN", "be"},
+		{"is", "VBZ", "be"},
+		{"are", "VBP", "be"},
+		{"'re", "VBP", "be"},
+		{"has", "VBZ", "have"},
+		{"written", "VBN", "write"},
+		{"mice", "NNS", "mouse"},
+		{"children", "NNS", "child"},
+		{"criteria", "NNS", "criterion"},
+		{"analyses", "NNS", "analysis"},
+		{"knives", "NNS", "knife"},
+		{"better", "JJR", "good"},
+		{"worst", "JJS", "bad"},
+		{"better", "RBR", "well"},
+		{"n't", "RB", "not"},
+		{"wo", "MD", "will"},
+
+		// Base forms are left alone.
+		{"lay", "VBP", "lay"},
+		{"found", "VB", "found"},
+		{"data", "NN", "data"},
+		{"The", "DT", "the"},
+		{"London", "NNP", "London"},
+
+		// Nouns.
+		{"dogs", "NNS", "dog"},
+		{"classes", "NNS", "class"},
+		{"churches", "NNS", "church"},
+		{"boxes", "NNS", "box"},
+		{"buzzes", "NNS", "buzz"},
+		{"sizes", "NNS", "size"},
+		{"cities", "NNS", "city"},
+		{"keys", "NNS", "key"},
+		{"potatoes", "NNS", "potato"},
+		{"shoes", "NNS", "shoe"},
+		{"houses", "NNS", "house"},
+		{"viruses", "NNS", "virus"},
+		{"uses", "NNS", "use"},
+		{"cases", "NNS", "case"},
+		{"responses", "NNS", "response"},
+		{"firemen", "NNS", "fireman"},
+		{"archives", "NNS", "archive"},
+		{"movies", "NNS", "movie"},
+		{"Americans", "NNPS", "American"},
+		{"Women", "NNPS", "Woman"},
+
+		// Verbs.
+		{"tries", "VBZ", "try"},
+		{"watches", "VBZ", "watch"},
+		{"goes", "VBZ", "go"},
+		{"uses", "VBZ", "use"},
+		{"realizes", "VBZ", "realize"},
+		{"'s", "VBZ", "be"},
+		{"walked", "VBD", "walk"},
+		{"hoped", "VBD", "hope"},
+		{"hopped", "VBD", "hop"},
+		{"tried", "VBD", "try"},
+		{"agreed", "VBD", "agree"},
+		{"needed", "VBD", "need"},
+		{"called", "VBD", "call"},
+		{"passed", "VBN", "pass"},
+		{"admitted", "VBN", "admit"},
+		{"visited", "VBD", "visit"},
+		{"opened", "VBD", "open"},
+		{"combined", "VBN", "combine"},
+		{"decided", "VBD", "decide"},
+		{"created", "VBN", "create"},
+		{"treated", "VBN", "treat"},
+		{"evaluated", "VBN", "evaluate"},
+		{"required", "VBN", "require"},
+		{"changed", "VBD", "change"},
+		{"belonged", "VBD", "belong"},
+		{"judged", "VBD", "judge"},
+		{"handled", "VBD", "handle"},
+		{"breathed", "VBD", "breathe"},
+		{"continued", "VBD", "continue"},
+		{"caused", "VBD", "cause"},
+		{"focused", "VBD", "focus"},
+		{"received", "VBD", "receive"},
+		{"forced", "VBD", "force"},
+		{"ignored", "VBD", "ignore"},
+		{"colored", "VBD", "color"},
+		{"offered", "VBD", "offer"},
+		{"compared", "VBN", "compare"},
+		{"measured", "VBN", "measure"},
+		{"controlled", "VBN", "control"},
+		{"developed", "VBN", "develop"},
+		{"escaped", "VBD", "escape"},
+		{"played", "VBD", "play"},
+		{"fixed", "VBD", "fix"},
+		{"running", "VBG", "run"},
+		{"making", "VBG", "make"},
+		{"being", "VBG", "be"},
+		{"seeing", "VBG", "see"},
+		{"going", "VBG", "go"},
+		{"lying", "VBG", "lie"},
+		{"trying", "VBG", "try"},
+		{"bringing", "VBG", "bring"},
+		{"changing", "VBG", "change"},
+		{"writing", "VBG", "write"},
+		{"beginning", "VBG", "begin"},
+		{"continuing", "VBG", "continue"},
+		{"happening", "VBG", "happen"},
+		{"telling", "VBG", "tell"},
+
+		// Adjectives and adverbs.
+		{"bigger", "JJR", "big"},
+		{"happiest", "JJS", "happy"},
+		{"larger", "JJR", "large"},
+		{"nicest", "JJS", "nice"},
+		{"simpler", "JJR", "simple"},
+		{"smaller", "JJR", "small"},
+		{"longer", "JJR", "long"},
+		{"younger", "JJR", "young"},
+		{"stranger", "JJR", "strange"},
+		{"freer", "JJR", "free"},
+		{"wider", "JJR", "wide"},
+		{"quieter", "JJR", "quiet"},
+		{"faster", "RBR", "fast"},
+		{"earlier", "RBR", "early"},
+	}
+
+	for _, c := range cases {
+		if observed := lemmatizer.Lemma(c.word, c.tag); observed != c.lemma {
+			t.Errorf("%s/%s: expected '%s', got '%s'", c.word, c.tag, c.lemma, observed)
+		}
+	}
+}
+
+func TestLemmatize(t *testing.T) {
+	tokens := []tag.Token{
+		{Text: "The", Tag: "DT"}, {Text: "mice", Tag: "NNS"},
+		{Text: "ran", Tag: "VBD"}, {Text: "faster", Tag: "RBR"},
+		{Text: "than", Tag: "IN"}, {Text: "the", Tag: "DT"},
+		{Text: "cats", Tag: "NNS"}, {Text: ".", Tag: "."},
+	}
+
+	expected := []string{"the", "mouse", "run", "fast", "than", "the", "cat", "."}
+	if lemmas := lemmatizer.Lemmatize(tokens); !reflect.DeepEqual(lemmas, expected) {
+		t.Errorf("expected %v, got %v", expected, lemmas)
+	}
+}
+
+func TestUsingExceptions(t *testing.T) {
+	l := lemma.NewLemmatizer(
+		lemma.UsingExceptions(lemma.Noun, map[string]string{
+			"Kubernetes": "kubernetes", "k8s": "kubernetes"}),
+		lemma.UsingExceptions(lemma.Verb, map[string]string{"grokked": "grok"}),
+	)
+
+	for _, c := range [][3]string{
+		{"Kubernetes", "NNP", "kubernetes"},
+		{"k8s", "NN", "kubernetes"},
+		{"grokked", "VBD", "grok"},
+		{"mice", "NNS", "mouse"},
+	} {
+		if observed := l.Lemma(c[0], c[1]); observed != c[2] {
+			t.Errorf("%s/%s: expected '%s', got '%s'", c[0], c[1], c[2], observed)
+		}
+	}
+}
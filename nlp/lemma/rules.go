@@ -0,0 +1,218 @@
+package lemma
+
+import "strings"
+
+// The rules below follow WordNet's morphy in removing inflectional suffixes,
+// but, since they can't check their candidates against a dictionary, they
+// also decide whether a stem has lost a final "e" (e.g., "hoped") or gained
+// a doubled consonant (e.g., "hopped"). Words for which these guesses are
+// wrong belong in the exception lists.
+
+// nounLemma returns the singular form of a plural noun.
+func nounLemma(word string) string {
+	n := len(word)
+	switch {
+	case strings.HasSuffix(word, "men"):
+		return word[:n-3] + "man"
+	case !strings.HasSuffix(word, "s") || strings.HasSuffix(word, "ss") ||
+		strings.HasSuffix(word, "us") || strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "ies"):
+		if n <= 4 {
+			return word[:n-1]
+		}
+		return word[:n-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "zzes"), strings.HasSuffix(word, "tzes"):
+		return word[:n-2]
+	case strings.HasSuffix(word, "oes"):
+		if n <= 5 {
+			return word[:n-1]
+		}
+		return word[:n-2]
+	case strings.HasSuffix(word, "uses"):
+		if n <= 4 || strings.HasSuffix(word, "ouses") || strings.HasSuffix(word, "auses") {
+			return word[:n-1]
+		}
+		return word[:n-2]
+	}
+	return word[:n-1]
+}
+
+// thirdPersonLemma returns the base form of a verb's third-person singular
+// form (e.g., "tries" becomes "try").
+func thirdPersonLemma(word string) string {
+	n := len(word)
+	switch {
+	case !strings.HasSuffix(word, "s") || strings.HasSuffix(word, "ss") || n <= 2:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return word[:n-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "zzes"), strings.HasSuffix(word, "tzes"),
+		strings.HasSuffix(word, "oes"):
+		return word[:n-2]
+	}
+	return word[:n-1]
+}
+
+// verbLemma returns the base form of a verb's past tense or participle,
+// which ends with suffix ("ed" or "ing").
+func verbLemma(word, suffix string) string {
+	stem, found := strings.CutSuffix(word, suffix)
+	if !found || !hasVowel(stem) {
+		return word
+	}
+
+	switch {
+	case suffix == "ed" && strings.HasSuffix(stem, "i"):
+		return stem[:len(stem)-1] + "y"
+	case suffix == "ed" && strings.HasSuffix(stem, "e"):
+		// E.g., "agreed" and "freed".
+		return stem + "e"
+	}
+	return restoreStem(stem)
+}
+
+// comparativeLemma returns the positive form of a comparative or
+// superlative, which ends with suffix ("er" or "est").
+func comparativeLemma(word, suffix string) string {
+	stem, found := strings.CutSuffix(word, suffix)
+	if !found || !hasVowel(stem) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(stem, "i"):
+		return stem[:len(stem)-1] + "y"
+	case strings.HasSuffix(stem, "e"):
+		// E.g., "freer" and "freest".
+		return stem + "e"
+	}
+	return restoreStem(stem)
+}
+
+// restoreStem undoes the spelling changes made to stem when a suffix that
+// begins with a vowel was added to it: a doubled final consonant is
+// undoubled, and a dropped final "e" is restored.
+func restoreStem(stem string) string {
+	n := len(stem)
+	if n >= 2 && stem[n-1] == stem[n-2] && !isVowel(stem, n-1) {
+		if strings.IndexByte("lsfz", stem[n-1]) >= 0 {
+			return stem
+		}
+		return stem[:n-1]
+	} else if needsE(stem) {
+		return stem + "e"
+	}
+	return stem
+}
+
+// needsE guesses whether stem originally ended with an "e".
+func needsE(stem string) bool {
+	n := len(stem)
+	if n < 2 {
+		return false
+	}
+
+	last, prev := stem[n-1], stem[n-2]
+	switch last {
+	case 'u', 'v', 'c', 's':
+		return true
+	case 'z':
+		return prev != 't'
+	case 'a', 'e', 'i', 'o', 'y', 'w', 'x', 'j', 'q':
+		return false
+	}
+
+	if !isVowel(stem, n-2) {
+		// The stem ends with two consonants.
+		switch last {
+		case 'l':
+			// E.g., "handled", but not "curled" or "howled".
+			return strings.IndexByte("lrw", prev) < 0
+		case 'g':
+			// E.g., "judged", "charged" and "changed", but not "longed" or
+			// "bringing".
+			if strings.IndexByte("rdl", prev) >= 0 {
+				return true
+			}
+			return prev == 'n' && n >= 3 && isShortVowel(stem, n-3) &&
+				strings.IndexByte("aeu", stem[n-3]) >= 0
+		case 'h':
+			// E.g., "bathed" and "breathed", but not "mouthed".
+			return prev == 't' && n >= 3 && isVowel(stem, n-3) &&
+				!strings.HasSuffix(stem, "outh") && !strings.HasSuffix(stem, "ooth")
+		}
+		return false
+	}
+
+	if !isShortVowel(stem, n-2) {
+		// The stem ends with two vowels and a consonant, as in "treated", or
+		// with "-iat" or "-uat", as in "initiated" and "evaluated".
+		return last == 'g' || (last == 't' && prev == 'a' &&
+			strings.IndexByte("iu", stem[n-3]) >= 0)
+	} else if syllables(stem) == 1 {
+		// E.g., "hoped", "timed" and "used".
+		return true
+	}
+
+	// The stem has more than one syllable and ends with a single vowel and
+	// a consonant, so the final syllable is most likely unstressed (e.g.,
+	// "opened" and "visited") unless it's one of the usual spellings of a
+	// stressed syllable that ends with an "e" (e.g., "combined" and
+	// "decided").
+	switch last {
+	case 'b', 'd', 'f', 'g', 'k', 'm':
+		return true
+	case 'l':
+		return prev != 'e'
+	case 'n', 'p', 'r':
+		return prev != 'e' && prev != 'o'
+	case 't':
+		return prev == 'a' || prev == 'o' || prev == 'u'
+	}
+	return false
+}
+
+// isVowel reports whether word[i] is a vowel. A "y" is a vowel unless it
+// begins the word or follows a vowel, and a "u" is a consonant after a "q".
+func isVowel(word string, i int) bool {
+	switch word[i] {
+	case 'a', 'e', 'i', 'o':
+		return true
+	case 'u':
+		return i == 0 || word[i-1] != 'q'
+	case 'y':
+		return i > 0 && !isVowel(word, i-1)
+	}
+	return false
+}
+
+// isShortVowel reports whether word[i] is a single vowel (i.e., one that
+// isn't preceded by another vowel).
+func isShortVowel(word string, i int) bool {
+	return isVowel(word, i) && (i == 0 || !isVowel(word, i-1))
+}
+
+// syllables returns the number of groups of vowels in word.
+func syllables(word string) int {
+	count := 0
+	for i := range word {
+		if isShortVowel(word, i) {
+			count++
+		}
+	}
+	return count
+}
+
+func hasVowel(word string) bool {
+	for i := range word {
+		if isVowel(word, i) {
+			return true
+		}
+	}
+	return false
+}
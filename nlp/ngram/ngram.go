@@ -0,0 +1,209 @@
+/*
+Package ngram implements functions for generating and counting word n-grams,
+skip-grams and character n-grams.
+
+The word generators work on token slices, such as those returned by any of
+the tokenize package's Tokenizers:
+
+	words := tokenize.NewTreebankWordTokenizer().Tokenize(text)
+	bigrams := ngram.NewGenerator().NGrams(words, 2)
+*/
+package ngram
+
+import (
+	"sort"
+	"strings"
+)
+
+// An NGram is a sequence of words.
+type NGram []string
+
+// String returns the NGram's words separated by spaces.
+func (g NGram) String() string {
+	return strings.Join(g, " ")
+}
+
+// A Generator produces the n-grams of a slice of words.
+type Generator struct {
+	isBoundary func(string) bool
+}
+
+// GeneratorOptFunc configures a Generator.
+type GeneratorOptFunc func(*Generator)
+
+// UsingBoundary prevents n-grams from containing, or spanning, any word for
+// which isBoundary returns true. For example,
+//
+//	ngram.NewGenerator(ngram.UsingBoundary(summarize.IsStopWord))
+//
+// only produces n-grams between stop words.
+func UsingBoundary(isBoundary func(string) bool) GeneratorOptFunc {
+	return func(g *Generator) {
+		g.isBoundary = isBoundary
+	}
+}
+
+// NewGenerator is a Generator constructor.
+func NewGenerator(opts ...GeneratorOptFunc) *Generator {
+	g := new(Generator)
+	for _, applyOpt := range opts {
+		applyOpt(g)
+	}
+	return g
+}
+
+// NGrams returns the n-grams of words (i.e., each sequence of n consecutive
+// words), in the order in which they occur.
+func (g *Generator) NGrams(words []string, n int) []NGram {
+	return g.SkipGrams(words, n, 0)
+}
+
+// SkipGrams returns the k-skip-n-grams of words: each sequence of n words
+// that occur in order with at most k words skipped between them in total.
+//
+// For example, the 1-skip-bigrams of "a b c" are "a b", "a c" and "b c".
+func (g *Generator) SkipGrams(words []string, n, k int) []NGram {
+	var grams []NGram
+	if n <= 0 || k < 0 {
+		return grams
+	}
+
+	for _, run := range g.runs(words) {
+		for start := range run {
+			indices := []int{start}
+			var extend func(skipped int)
+			extend = func(skipped int) {
+				if len(indices) == n {
+					gram := make(NGram, n)
+					for i, j := range indices {
+						gram[i] = run[j]
+					}
+					grams = append(grams, gram)
+					return
+				}
+				last := indices[len(indices)-1]
+				for skip := 0; skip <= k-skipped && last+skip+1 < len(run); skip++ {
+					indices = append(indices, last+skip+1)
+					extend(skipped + skip)
+					indices = indices[:len(indices)-1]
+				}
+			}
+			extend(0)
+		}
+	}
+
+	return grams
+}
+
+// runs splits words at the Generator's boundaries.
+func (g *Generator) runs(words []string) [][]string {
+	if g.isBoundary == nil {
+		return [][]string{words}
+	}
+
+	var runs [][]string
+	start := 0
+	for i, word := range words {
+		if g.isBoundary(word) {
+			if i > start {
+				runs = append(runs, words[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(words) {
+		runs = append(runs, words[start:])
+	}
+
+	return runs
+}
+
+// CharNGrams returns the character n-grams of text (i.e., each sequence of n
+// consecutive characters), in the order in which they occur.
+func CharNGrams(text string, n int) []string {
+	var grams []string
+	if n <= 0 {
+		return grams
+	}
+
+	// offsets holds the byte offset of each character, plus the end of text.
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	for i := 0; i+n < len(offsets); i++ {
+		grams = append(grams, text[offsets[i]:offsets[i+n]])
+	}
+
+	return grams
+}
+
+// A Frequency is an NGram along with the number of times it occurred.
+type Frequency struct {
+	NGram NGram
+	Count int
+}
+
+// A Counter counts occurrences of n-grams.
+type Counter struct {
+	counts map[string]*Frequency
+}
+
+// NewCounter is a Counter constructor.
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[string]*Frequency)}
+}
+
+// Add counts an occurrence of each of grams.
+func (c *Counter) Add(grams ...NGram) {
+	for _, gram := range grams {
+		key := gram.key()
+		if f, found := c.counts[key]; found {
+			f.Count++
+		} else {
+			c.counts[key] = &Frequency{NGram: gram, Count: 1}
+		}
+	}
+}
+
+// Count returns the number of times that gram has occurred.
+func (c *Counter) Count(gram NGram) int {
+	if f, found := c.counts[gram.key()]; found {
+		return f.Count
+	}
+	return 0
+}
+
+// Len returns the number of distinct n-grams that have occurred.
+func (c *Counter) Len() int {
+	return len(c.counts)
+}
+
+// Top returns the k most frequent n-grams, in descending order of frequency.
+// N-grams that occurred equally often are ordered alphabetically. If k is
+// negative, all of the n-grams are returned.
+func (c *Counter) Top(k int) []Frequency {
+	top := make([]Frequency, 0, len(c.counts))
+	for _, f := range c.counts {
+		top = append(top, *f)
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].NGram.key() < top[j].NGram.key()
+	})
+
+	if k >= 0 && k < len(top) {
+		top = top[:k]
+	}
+	return top
+}
+
+// key identifies an NGram, regardless of any spaces in its words.
+func (g NGram) key() string {
+	return strings.Join(g, "\x00")
+}
@@ -0,0 +1,129 @@
+package ngram_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jdkato/twine/nlp/ngram"
+)
+
+func toStrings(grams []ngram.NGram) []string {
+	s := make([]string, len(grams))
+	for i, gram := range grams {
+		s[i] = gram.String()
+	}
+	return s
+}
+
+func TestNGrams(t *testing.T) {
+	words := strings.Fields("the cat sat on the mat")
+	g := ngram.NewGenerator()
+
+	cases := []struct {
+		n        int
+		expected []string
+	}{
+		{1, []string{"the", "cat", "sat", "on", "the", "mat"}},
+		{2, []string{"the cat", "cat sat", "sat on", "on the", "the mat"}},
+		{3, []string{"the cat sat", "cat sat on", "sat on the", "on the mat"}},
+		{6, []string{"the cat sat on the mat"}},
+		{7, []string{}},
+		{0, []string{}},
+	}
+
+	for _, c := range cases {
+		if observed := toStrings(g.NGrams(words, c.n)); !reflect.DeepEqual(observed, c.expected) {
+			t.Errorf("n = %d: expected %v, got %v", c.n, c.expected, observed)
+		}
+	}
+}
+
+func TestSkipGrams(t *testing.T) {
+	words := strings.Fields("insurgents killed in ongoing fighting")
+	g := ngram.NewGenerator()
+
+	// From Guthrie et al. (2006), "A Closer Look at Skip-gram Modelling".
+	expected := []string{
+		"insurgents killed", "insurgents in", "insurgents ongoing",
+		"killed in", "killed ongoing", "killed fighting",
+		"in ongoing", "in fighting",
+		"ongoing fighting",
+	}
+	if observed := toStrings(g.SkipGrams(words, 2, 2)); !reflect.DeepEqual(observed, expected) {
+		t.Errorf("2-skip-bigrams: expected %v, got %v", expected, observed)
+	}
+
+	expected = []string{
+		"insurgents killed in", "insurgents killed ongoing", "insurgents in ongoing",
+		"killed in ongoing", "killed in fighting", "killed ongoing fighting",
+		"in ongoing fighting",
+	}
+	if observed := toStrings(g.SkipGrams(words, 3, 1)); !reflect.DeepEqual(observed, expected) {
+		t.Errorf("1-skip-trigrams: expected %v, got %v", expected, observed)
+	}
+}
+
+func TestUsingBoundary(t *testing.T) {
+	stop := map[string]bool{"the": true, "on": true, "a": true}
+	g := ngram.NewGenerator(ngram.UsingBoundary(func(w string) bool {
+		return stop[w]
+	}))
+
+	words := strings.Fields("the black cat sat on a red mat the end")
+	expected := []string{"black cat", "cat sat", "red mat"}
+	if observed := toStrings(g.NGrams(words, 2)); !reflect.DeepEqual(observed, expected) {
+		t.Errorf("expected %v, got %v", expected, observed)
+	}
+
+	expected = []string{"black sat"}
+	if observed := toStrings(g.SkipGrams(words, 2, 1)[1:2]); !reflect.DeepEqual(observed, expected) {
+		t.Errorf("expected %v, got %v", expected, observed)
+	}
+}
+
+func TestCharNGrams(t *testing.T) {
+	cases := []struct {
+		text     string
+		n        int
+		expected []string
+	}{
+		{"text", 2, []string{"te", "ex", "xt"}},
+		{"naïve", 3, []string{"naï", "aïv", "ïve"}},
+		{"日本語", 1, []string{"日", "本", "語"}},
+		{"ab", 3, nil},
+	}
+
+	for _, c := range cases {
+		if observed := ngram.CharNGrams(c.text, c.n); !reflect.DeepEqual(observed, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.text, c.expected, observed)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	words := strings.Fields("to be or not to be that is the question to be")
+	c := ngram.NewCounter()
+	c.Add(ngram.NewGenerator().NGrams(words, 2)...)
+
+	if count := c.Count(ngram.NGram{"to", "be"}); count != 3 {
+		t.Errorf("expected 3 occurrences of 'to be', got %d", count)
+	}
+	if count := c.Count(ngram.NGram{"be", "to"}); count != 0 {
+		t.Errorf("expected 0 occurrences of 'be to', got %d", count)
+	}
+
+	top := c.Top(3)
+	expected := []ngram.Frequency{
+		{NGram: ngram.NGram{"to", "be"}, Count: 3},
+		{NGram: ngram.NGram{"be", "or"}, Count: 1},
+		{NGram: ngram.NGram{"be", "that"}, Count: 1},
+	}
+	if !reflect.DeepEqual(top, expected) {
+		t.Errorf("expected %v, got %v", expected, top)
+	}
+
+	if all := c.Top(-1); len(all) != c.Len() {
+		t.Errorf("expected %d n-grams, got %d", c.Len(), len(all))
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Package normalize implements a pipeline of text normalizations, such as
+Unicode normalization, case folding and the folding of typographic
+punctuation into ASCII.
+
+A Pipeline applies a sequence of Steps to a string and, along with the
+normalized text, returns an OffsetMap that maps byte offsets in the normalized
+text back to the original:
+
+	p := normalize.NewPipeline(normalize.HTMLEntities, normalize.NFC, normalize.Quotes)
+	text, offsets := p.Normalize("&ldquo;Café&rdquo;")
+	// text == `"Café"` and offsets.Original(1) == 7
+*/
+package normalize
+
+import (
+	"sort"
+	"strings"
+)
+
+// An Edit replaces the bytes Start:End of a Step's input with Text.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// A Step normalizes text by returning the Edits to make to it, which must be
+// in order and may not overlap.
+type Step func(text string) []Edit
+
+// A Pipeline applies a sequence of Steps.
+type Pipeline struct {
+	steps []Step
+}
+
+// NewPipeline creates a Pipeline that applies steps in the given order.
+func NewPipeline(steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// String returns the normalized form of text.
+func (p *Pipeline) String(text string) string {
+	for _, step := range p.steps {
+		text, _ = apply(text, step(text))
+	}
+	return text
+}
+
+// Normalize returns the normalized form of text along with an OffsetMap from
+// the normalized text to text.
+func (p *Pipeline) Normalize(text string) (string, *OffsetMap) {
+	m := new(OffsetMap)
+	for _, step := range p.steps {
+		var spans layer
+		text, spans = apply(text, step(text))
+		m.layers = append(m.layers, spans)
+	}
+	return text, m
+}
+
+// A layer holds the spans that make up a Step's output.
+type layer []span
+
+// A span is a piece of a Step's output: either a copy of its input
+// (replaced is false) or the replacement of an Edit.
+type span struct {
+	out, outEnd int
+	in, inEnd   int
+	replaced    bool
+}
+
+// apply makes edits to text, returning the result along with the spans that
+// make it up.
+func apply(text string, edits []Edit) (string, layer) {
+	var sb strings.Builder
+	var spans layer
+
+	copied := func(start, end int) {
+		if start < end {
+			out := sb.Len()
+			sb.WriteString(text[start:end])
+			spans = append(spans, span{out, sb.Len(), start, end, false})
+		}
+	}
+
+	last := 0
+	for _, e := range edits {
+		copied(last, e.Start)
+		out := sb.Len()
+		sb.WriteString(e.Text)
+		spans = append(spans, span{out, sb.Len(), e.Start, e.End, true})
+		last = e.End
+	}
+	copied(last, len(text))
+
+	return sb.String(), spans
+}
+
+// An OffsetMap maps byte offsets in normalized text to byte offsets in the
+// original text.
+type OffsetMap struct {
+	// layers holds the spans of each Step's output, in order.
+	layers []layer
+}
+
+// Original returns the offset in the original text that corresponds to
+// offset, which is an offset in the normalized text. An offset within a
+// replacement (e.g., the second byte of the "..." that replaced "…") maps to
+// the start of the text that was replaced.
+func (m *OffsetMap) Original(offset int) int {
+	for i := len(m.layers) - 1; i >= 0; i-- {
+		offset = m.layers[i].original(offset, false)
+	}
+	return offset
+}
+
+// OriginalSpan returns the span of the original text that corresponds to
+// [start, end) in the normalized text. The span includes all of the text that
+// was replaced by any part of [start, end).
+func (m *OffsetMap) OriginalSpan(start, end int) (int, int) {
+	for i := len(m.layers) - 1; i >= 0; i-- {
+		start = m.layers[i].original(start, false)
+		end = m.layers[i].original(end, true)
+	}
+	return start, end
+}
+
+// original maps offset from a Step's output to its input. If isEnd is true,
+// offset is the end of a span, so it maps to the end of any replacement that
+// it falls within.
+func (spans layer) original(offset int, isEnd bool) int {
+	if len(spans) == 0 {
+		return offset
+	}
+
+	// Find the first span that ends after offset (or, for an end offset, at
+	// or after it), skipping deletions.
+	i := sort.Search(len(spans), func(i int) bool {
+		if isEnd {
+			return spans[i].outEnd >= offset
+		}
+		return spans[i].outEnd > offset
+	})
+	for i < len(spans)-1 && spans[i].out == spans[i].outEnd {
+		i++
+	}
+	if i == len(spans) {
+		s := spans[len(spans)-1]
+		return s.inEnd + offset - s.outEnd
+	}
+
+	s := spans[i]
+	switch {
+	case !s.replaced:
+		return s.in + offset - s.out
+	case isEnd && offset > s.out:
+		return s.inEnd
+	}
+	return s.in
+}
@@ -0,0 +1,111 @@
+package normalize_test
+
+import (
+	"testing"
+
+	"github.com/jdkato/twine/nlp/normalize"
+)
+
+func TestSteps(t *testing.T) {
+	cases := []struct {
+		step     normalize.Step
+		text     string
+		expected string
+	}{
+		{normalize.NFC, "cafe\u0301", "caf\u00e9"},
+		{normalize.NFC, "ﬁne", "ﬁne"},
+		{normalize.NFKC, "ﬁne Ａ①", "fine A1"},
+		{normalize.CaseFold, "Straße ΣΊΣΥΦΟΣ", "strasse σίσυφοσ"},
+		{normalize.CaseFold, "A\xffBCDE", "a\xffbcde"},
+		{normalize.CaseFold, "AB\xff", "ab\xff"},
+		{normalize.CaseFold, "\xe2\x80Ä\xf0", "\xe2\x80ä\xf0"},
+		{normalize.Quotes, "“It’s «ok»”", `"It's "ok""`},
+		{normalize.Dashes, "1990–1995 — −2", "1990-1995 - -2"},
+		{normalize.Ellipses, "Wait…", "Wait..."},
+		{normalize.Spaces, "no\u00a0break\u200b here\u202f!\ufeff", "no break here !"},
+		{normalize.HTMLEntities, "Q&amp;A &ldquo;&#8217;&#x41;&rdquo; &bogus; AT&T", "Q&A “’A” &bogus; AT&T"},
+		{normalize.Accents, "Crème brûlée, façade, Ørsted", "Creme brulee, facade, Ørsted"},
+	}
+
+	for _, c := range cases {
+		p := normalize.NewPipeline(c.step)
+		if observed := p.String(c.text); observed != c.expected {
+			t.Errorf("%q: expected %q, got %q", c.text, c.expected, observed)
+		}
+		if observed, _ := p.Normalize(c.text); observed != c.expected {
+			t.Errorf("%q: Normalize: expected %q, got %q", c.text, c.expected, observed)
+		}
+	}
+}
+
+func TestPunctuation(t *testing.T) {
+	text := "“It’s&rsquo;” – and…\u00a0done"
+	if s := normalize.Punctuation.String(text); s != `"It's&rsquo;" - and... done` {
+		t.Errorf("Punctuation: got %q", s)
+	}
+
+	p := normalize.NewPipeline(
+		normalize.HTMLEntities, normalize.Quotes, normalize.Dashes,
+		normalize.Ellipses, normalize.Spaces)
+	if s := p.String(text); s != `"It's'" - and... done` {
+		t.Errorf("HTMLEntities + Punctuation: got %q", s)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	p := normalize.NewPipeline(
+		normalize.HTMLEntities, normalize.NFC, normalize.Quotes,
+		normalize.Ellipses, normalize.CaseFold)
+
+	text := "&ldquo;Cafe\u0301&rdquo;\u2026 Done"
+	normalized, offsets := p.Normalize(text)
+	if expected := `"café"... done`; normalized != expected {
+		t.Fatalf("expected %q, got %q", expected, normalized)
+	}
+	if s := p.String(text); s != normalized {
+		t.Errorf("String: expected %q, got %q", normalized, s)
+	}
+
+	cases := []struct {
+		start, end         int // in the normalized text
+		origStart, origEnd int // in the original text
+	}{
+		{0, 1, 0, 7},     // `"` from "&ldquo;"
+		{1, 4, 7, 10},    // "caf"
+		{1, 6, 7, 13},    // "café" from "Café"
+		{4, 6, 10, 13},   // "é" from "é"
+		{7, 8, 20, 23},   // "." from "…"
+		{7, 10, 20, 23},  // "..." from "…"
+		{11, 15, 24, 28}, // "done"
+		{15, 15, 28, 28},
+	}
+
+	for _, c := range cases {
+		start, end := offsets.OriginalSpan(c.start, c.end)
+		if start != c.origStart || end != c.origEnd {
+			t.Errorf("%q: expected [%d, %d), got [%d, %d)",
+				normalized[c.start:c.end], c.origStart, c.origEnd, start, end)
+		}
+		if offset := offsets.Original(c.start); offset != c.origStart {
+			t.Errorf("%d: expected %d, got %d", c.start, c.origStart, offset)
+		}
+	}
+}
+
+func TestDeletions(t *testing.T) {
+	text := "\ufeffzero\u200bwidth\u200b"
+	normalized, offsets := normalize.NewPipeline(normalize.Spaces).Normalize(text)
+	if normalized != "zerowidth" {
+		t.Fatalf("expected %q, got %q", "zerowidth", normalized)
+	}
+
+	for _, c := range [][3]int{{0, 4, 3}, {4, 9, 10}} {
+		start, end := offsets.OriginalSpan(c[0], c[1])
+		if text[start:end] != normalized[c[0]:c[1]] {
+			t.Errorf("expected %q, got %q", normalized[c[0]:c[1]], text[start:end])
+		}
+		if start != c[2] {
+			t.Errorf("expected %d, got %d", c[2], start)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package normalize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// NFC composes characters, as in Unicode Normalization Form C.
+	NFC Step = unicodeForm(norm.NFC)
+
+	// NFKC composes characters and replaces compatibility characters (e.g.,
+	// ligatures and full-width forms), as in Unicode Normalization Form KC.
+	NFKC Step = unicodeForm(norm.NFKC)
+
+	// CaseFold folds case (e.g., "Straße" becomes "strasse").
+	CaseFold Step = caseFold
+
+	// Quotes replaces curly quotes, primes and guillemets with their ASCII
+	// equivalents.
+	Quotes Step = replaceRunes(map[rune]string{
+		'\u2018': "'", '\u2019': "'", '\u201a': "'", '\u201b': "'",
+		'\u2032': "'", '\u2039': "'", '\u203a': "'",
+		'\u201c': `"`, '\u201d': `"`, '\u201e': `"`, '\u201f': `"`,
+		'\u2033': `"`, '\u00ab': `"`, '\u00bb': `"`,
+	})
+
+	// Dashes replaces hyphens, dashes and minus signs with "-".
+	Dashes Step = replaceRunes(map[rune]string{
+		'\u2010': "-", '\u2011': "-", '\u2012': "-", '\u2013': "-",
+		'\u2014': "-", '\u2015': "-", '\u2212': "-", '\ufe58': "-",
+		'\ufe63': "-", '\uff0d': "-",
+	})
+
+	// Ellipses replaces the ellipsis character with "...".
+	Ellipses Step = replaceRunes(map[rune]string{'\u2026': "..."})
+
+	// Spaces removes zero-width characters (U+200B ZERO WIDTH SPACE, U+200C
+	// ZERO WIDTH NON-JOINER, U+200D ZERO WIDTH JOINER, U+2060 WORD JOINER and
+	// U+FEFF BYTE ORDER MARK) and replaces non-breaking and other fixed-width
+	// spaces with " ".
+	//
+	// Since joiners are removed, Spaces breaks up emoji sequences and changes
+	// the rendering of scripts that rely on them (e.g., Persian).
+	Spaces Step = foldSpaces
+
+	// HTMLEntities decodes HTML entities (e.g., "&amp;" and "&#8217;").
+	HTMLEntities Step = decodeEntities
+
+	// Accents removes diacritics (e.g., "café" becomes "cafe"). Characters
+	// that don't decompose into a base character and combining marks, such
+	// as "ø", are left alone.
+	Accents Step = stripAccents
+)
+
+// Punctuation is a Pipeline that folds typographic punctuation and spaces
+// into ASCII. It makes the same replacements of Unicode characters as the
+// tokenize and strcase packages' sanitizers, but it doesn't decode HTML
+// entities as tokenize's does for "&rsquo;"; to do so, run HTMLEntities
+// first:
+//
+//	NewPipeline(HTMLEntities, Quotes, Dashes, Ellipses, Spaces)
+var Punctuation = NewPipeline(Quotes, Dashes, Ellipses, Spaces)
+
+// replaceRunes creates a Step that replaces each of the given runes.
+func replaceRunes(replacements map[rune]string) Step {
+	return func(text string) []Edit {
+		var edits []Edit
+		for i, r := range text {
+			if s, found := replacements[r]; found {
+				edits = append(edits, Edit{i, i + utf8.RuneLen(r), s})
+			}
+		}
+		return edits
+	}
+}
+
+// unicodeForm creates a Step that normalizes text to form, one segment (a
+// starter and its combining characters) at a time.
+func unicodeForm(form norm.Form) Step {
+	return func(text string) []Edit {
+		if form.IsNormalString(text) {
+			return nil
+		}
+		return segments(text, form, form.String)
+	}
+}
+
+// segments applies f to each of text's normalization segments (according to
+// form), returning the Edits for those it changes.
+func segments(text string, form norm.Form, f func(string) string) []Edit {
+	var edits []Edit
+	for i := 0; i < len(text); {
+		n := form.NextBoundaryInString(text[i:], true)
+		if n <= 0 {
+			n = len(text) - i
+		}
+		segment := text[i : i+n]
+		if s := f(segment); s != segment {
+			edits = append(edits, Edit{i, i + n, s})
+		}
+		i += n
+	}
+	return edits
+}
+
+var folder = cases.Fold()
+
+// caseFold folds each rune separately, which is possible because (unlike
+// lowercasing) case folding doesn't depend on context. Invalid UTF-8 is left
+// alone.
+func caseFold(text string) []Edit {
+	var edits []Edit
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		char := text[i : i+size]
+		if r >= utf8.RuneSelf && !(r == utf8.RuneError && size == 1) || r >= 'A' && r <= 'Z' {
+			if s := folder.String(char); s != char {
+				edits = append(edits, Edit{i, i + size, s})
+			}
+		}
+		i += size
+	}
+	return edits
+}
+
+var zeroWidth = map[rune]bool{
+	'\u200b': true, '\u200c': true, '\u200d': true, '\u2060': true, '\ufeff': true,
+}
+
+func foldSpaces(text string) []Edit {
+	var edits []Edit
+	for i, r := range text {
+		if zeroWidth[r] {
+			edits = append(edits, Edit{i, i + utf8.RuneLen(r), ""})
+		} else if r != ' ' && unicode.Is(unicode.Zs, r) {
+			edits = append(edits, Edit{i, i + utf8.RuneLen(r), " "})
+		}
+	}
+	return edits
+}
+
+var entityRE = regexp.MustCompile(`&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+
+func decodeEntities(text string) []Edit {
+	if !strings.Contains(text, "&") {
+		return nil
+	}
+
+	var edits []Edit
+	for _, loc := range entityRE.FindAllStringIndex(text, -1) {
+		entity := text[loc[0]:loc[1]]
+		if s := html.UnescapeString(entity); s != entity {
+			edits = append(edits, Edit{loc[0], loc[1], s})
+		}
+	}
+	return edits
+}
+
+func stripAccents(text string) []Edit {
+	return segments(text, norm.NFD, func(segment string) string {
+		decomposed := norm.NFD.String(segment)
+		stripped := strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, decomposed)
+		if stripped == decomposed {
+			return segment
+		}
+		return norm.NFC.String(stripped)
+	})
+}
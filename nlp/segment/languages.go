@@ -0,0 +1,165 @@
+package segment
+
+import (
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/neurosnap/sentences.v1"
+	"gopkg.in/neurosnap/sentences.v1/data"
+)
+
+// The non-English models are the Punkt models distributed with
+// https://github.com/neurosnap/sentences (which were converted from NLTK's),
+// compressed.
+//
+//go:embed data/*.json.gz
+var models embed.FS
+
+// languageCodes maps ISO 639-1 codes to the names of supported languages.
+var languageCodes = map[string]string{
+	"en": "english",
+	"es": "spanish",
+	"fr": "french",
+	"de": "german",
+	"it": "italian",
+	"pt": "portuguese",
+	"nl": "dutch",
+}
+
+// abbreviations supplements each model's abbreviations with common ones
+// that its training data lacked. They're lowercase and lack their final
+// period, as Punkt expects.
+var abbreviations = map[string][]string{
+	"english": {"sgt", "gov", "no", "mt"},
+	"spanish": {
+		"sr", "sra", "srta", "sres", "dr", "dra", "lic", "ing", "prof", "profa",
+		"arq", "ud", "uds", "vd", "vds", "pág", "págs", "núm", "tel", "etc", "ej",
+		"p.ej", "aprox", "av", "avda", "apdo", "cía", "dpto", "depto", "atte",
+		"gral", "sto", "sta", "vol", "cap", "art", "fig", "cf", "ee.uu", "ss",
+		"admón", "párr", "párrf", "hs", "min", "no", "n°", "nº", "ene", "feb",
+		"mar", "abr", "may", "jun", "jul", "ago", "sep", "sept", "oct", "nov",
+		"dic", "lun", "mié", "jue", "vie", "sáb", "dom",
+	},
+	"french": {
+		"m", "mm", "mme", "mmes", "mlle", "mlles", "dr", "pr", "me", "mgr", "st",
+		"ste", "etc", "cf", "p", "pp", "vol", "chap", "fig", "éd", "env", "av",
+		"bd", "ex", "tél", "janv", "févr", "avr", "juil", "sept", "oct", "nov",
+		"déc", "c.-à-d", "cie", "ltd",
+	},
+	"german": {
+		"bzw", "usw", "z.b", "u.a", "d.h", "ca", "nr", "str", "dr", "prof", "hr",
+		"fr", "hrsg", "vgl", "ggf", "inkl", "evtl", "bspw", "sog", "mio", "mrd",
+		"jh", "bd", "abs", "tel", "zzgl", "o.ä", "u.ä", "etc", "ebd", "geb",
+		"gest", "allg", "insb", "jan", "febr", "sept", "okt", "nov", "dez",
+	},
+	"italian": {
+		"sig", "sigg", "sig.ra", "dott", "dott.ssa", "prof", "ing", "avv", "arch",
+		"geom", "rag", "on", "ecc", "pag", "pagg", "es", "cfr", "vol", "cap",
+		"art", "tel", "ca", "mons", "p.es", "s.p.a",
+	},
+	"portuguese": {
+		"sr", "sra", "srta", "dr", "dra", "prof", "profa", "eng", "av", "pág",
+		"págs", "etc", "ex", "p.ex", "cf", "tel", "nº", "vol", "cap", "art",
+		"fig", "séc", "apto", "ltda", "cia",
+	},
+	"dutch": {
+		"dhr", "mevr", "mr", "dr", "drs", "ir", "ing", "prof", "bijv", "bv",
+		"enz", "blz", "nr", "o.a", "d.w.z", "m.a.w", "i.p.v", "t.a.v", "vnl",
+		"evt", "jl", "jr", "sr", "st", "ca", "resp", "zgn", "etc", "incl",
+		"excl", "e.d", "e.a", "m.b.t", "n.a.v",
+	},
+}
+
+// titles are the abbreviations of each language that precede names (e.g.,
+// "Dr."), which is why they never end a sentence.
+var titles = map[string][]string{
+	"spanish": {
+		"sr", "sra", "srta", "sres", "dr", "dra", "lic", "ing", "prof", "profa",
+		"arq", "gral", "sto", "sta",
+	},
+	"french": {
+		"m", "mm", "mme", "mmes", "mlle", "mlles", "dr", "pr", "me", "mgr",
+		"st", "ste",
+	},
+	"german": {"hr", "fr", "dr", "prof"},
+	"italian": {
+		"sig", "sigg", "sig.ra", "dott", "dott.ssa", "prof", "ing", "avv",
+		"arch", "geom", "rag", "on", "mons",
+	},
+	"portuguese": {"sr", "sra", "srta", "dr", "dra", "prof", "profa", "eng"},
+	"dutch":      {"dhr", "mevr", "mr", "dr", "drs", "ir", "ing", "prof"},
+}
+
+// NewPunktSentenceTokenizerFor creates a new PunktSentenceTokenizer for the
+// given language, which may be a name (e.g., "Spanish") or an ISO 639-1 code
+// (e.g., "es"). The options add to the language's built-in vocabulary.
+//
+// The supported languages are English, Spanish, French, German, Italian,
+// Portuguese and Dutch.
+func NewPunktSentenceTokenizerFor(language string, opts ...SegmenterOptFunc) (*punktSentenceTokenizer, error) {
+	name, err := languageName(language)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := loadModel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	vocab := newVocabulary(abbreviations[name], titles[name], opts)
+	tokenizer, err := newSentenceTokenizer(training, vocab)
+	if err != nil {
+		return nil, err
+	}
+
+	return &punktSentenceTokenizer{tokenizer: tokenizer}, nil
+}
+
+// languageName returns the name of a supported language from either its
+// name or its code.
+func languageName(language string) (string, error) {
+	language = strings.ToLower(language)
+	if name, found := languageCodes[language]; found {
+		return name, nil
+	}
+	for _, name := range languageCodes {
+		if name == language {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("segment: unsupported language %q", language)
+}
+
+// loadModel loads the Punkt model of a supported language.
+func loadModel(name string) (*sentences.Storage, error) {
+	if name == "english" {
+		b, err := data.Asset("data/english.json")
+		if err != nil {
+			return nil, err
+		}
+		return sentences.LoadTraining(b)
+	}
+
+	compressed, err := models.ReadFile("data/" + name + ".json.gz")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return sentences.LoadTraining(b)
+}
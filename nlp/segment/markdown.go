@@ -0,0 +1,422 @@
+package segment
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// A BlockKind is the kind of a Markdown Block.
+type BlockKind int
+
+const (
+	Paragraph  BlockKind = iota // A paragraph.
+	Heading                     // An ATX ("# Title") or setext heading.
+	ListItem                    // An item (or a paragraph of an item) in a list.
+	TableCell                   // A cell of a table, including its header.
+	BlockQuote                  // A paragraph in a block quote.
+	Footnote                    // The text of a footnote definition.
+)
+
+var blockKinds = map[BlockKind]string{
+	Paragraph:  "Paragraph",
+	Heading:    "Heading",
+	ListItem:   "ListItem",
+	TableCell:  "TableCell",
+	BlockQuote: "BlockQuote",
+	Footnote:   "Footnote",
+}
+
+// String returns the name of the BlockKind.
+func (k BlockKind) String() string {
+	return blockKinds[k]
+}
+
+// A Block is a part of a Markdown document that contains prose, such as a
+// heading or a list item. Sentences never cross the boundaries of Blocks.
+type Block struct {
+	Kind  BlockKind // The kind of block.
+	Index int       // The zero-based position of the block in its document.
+	Start int       // The byte offset of the block's content.
+	End   int       // The byte offset just past the block's content.
+}
+
+// markdownSegmenter segments the prose of a Markdown document, treating the
+// boundaries of its blocks as sentence boundaries.
+type markdownSegmenter struct {
+	prose Segmenter
+}
+
+// MarkdownSegmenterOptFunc configures a Markdown Segmenter.
+type MarkdownSegmenterOptFunc func(*markdownSegmenter)
+
+// UsingProseSegmenter segments each block's prose using s rather than the
+// default, NewPunktSentenceTokenizer.
+func UsingProseSegmenter(s Segmenter) MarkdownSegmenterOptFunc {
+	return func(m *markdownSegmenter) {
+		m.prose = s
+	}
+}
+
+// NewMarkdownSegmenter creates a new Segmenter for Markdown.
+//
+// Headings, list items, table cells, footnotes and paragraphs (including
+// those in block quotes) are segmented separately, so a heading without a
+// period doesn't run into the sentence that follows it. Code blocks, HTML
+// blocks, YAML front matter, thematic breaks and link reference definitions
+// are skipped. Inline markup (e.g., emphasis and links) is left in each
+// Sentence, whose Block is the block that it came from.
+//
+// The block quote markers ("> ") at the start of a sentence's continuation
+// lines and the "!" that starts an image are replaced with spaces in its
+// Text.
+func NewMarkdownSegmenter(opts ...MarkdownSegmenterOptFunc) *markdownSegmenter {
+	m := &markdownSegmenter{}
+
+	for _, applyOpt := range opts {
+		applyOpt(m)
+	}
+
+	if m.prose == nil {
+		m.prose = NewPunktSentenceTokenizer()
+	}
+
+	return m
+}
+
+// Segment splits text into sentences.
+func (m *markdownSegmenter) Segment(text string) []string {
+	sents := []string{}
+	for _, s := range m.Sentences(text) {
+		sents = append(sents, s.Text)
+	}
+	return sents
+}
+
+// Sentences splits text into sentences, along with their positions in text
+// and the blocks that they came from.
+func (m *markdownSegmenter) Sentences(text string) []Sentence {
+	p := newBlockParser(text)
+	p.parse()
+
+	sents := []Sentence{}
+
+	offset, runes := 0, 0
+	for _, b := range p.blocks {
+		runes += utf8.RuneCountInString(text[offset:b.Start])
+		offset = b.Start
+
+		for _, s := range m.prose.Sentences(string(p.masked[b.Start:b.End])) {
+			s.Start += b.Start
+			s.End += b.Start
+			s.RuneStart += runes
+			s.RuneEnd += runes
+			s.Block = b
+			sents = append(sents, s)
+		}
+	}
+
+	return sents
+}
+
+var (
+	reMarkdownFence   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	reMarkdownBreak   = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	reSetextUnderline = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	reMarkdownHeading = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+|$)`)
+	reHeadingClosing  = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	reMarkdownItem    = regexp.MustCompile(`^[ \t]*(?:[-+*]|\d{1,9}[.)])(?:[ \t]+(?:\[[ xX]\](?:[ \t]+|$))?|$)`)
+	reReferenceDef    = regexp.MustCompile(`^ {0,3}\[[^\]^][^\]]*\]:`)
+	reFootnoteDef     = regexp.MustCompile(`^ {0,3}\[\^[^\]\s]+\]:[ \t]*`)
+	reTableDelim      = regexp.MustCompile(`^[ \t]*\|?(?:[ \t]*:?-+:?[ \t]*\|)*[ \t]*:?-+:?[ \t]*\|?[ \t]*$`)
+	reHTMLBlock       = regexp.MustCompile(`(?i)^ {0,3}<(?:(pre|script|style|textarea)(?:[\s>]|$)|(!--)|/?(?:address|article|aside|blockquote|details|dialog|div|dl|fieldset|figcaption|figure|footer|form|h[1-6]|header|hr|li|main|nav|ol|p|section|table|tbody|td|th|thead|tr|ul)(?:[\s/>]|$))`)
+)
+
+// A markdownLine locates a line of text, excluding its line ending.
+type markdownLine struct {
+	start, end int
+}
+
+// blockParser finds the prose blocks of a Markdown document.
+type blockParser struct {
+	text   string
+	lines  []markdownLine
+	masked []byte // text, with block quote markers blanked out
+	blocks []*Block
+	open   *Block // the block that the next line may continue
+}
+
+func newBlockParser(text string) *blockParser {
+	p := &blockParser{text: text, masked: []byte(text)}
+	for start := 0; start < len(text); {
+		end, next := len(text), len(text)
+		if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end, next = start+i, start+i+1
+		}
+		if end > start && text[end-1] == '\r' {
+			end--
+		}
+		p.lines = append(p.lines, markdownLine{start: start, end: end})
+		start = next
+	}
+	return p
+}
+
+func (p *blockParser) parse() {
+	i := p.frontMatter()
+
+	blank, list, table := true, false, false
+	depth := 0
+	for ; i < len(p.lines); i++ {
+		start, d := p.quotes(p.lines[i])
+		end := p.lines[i].end
+		content := p.text[start:end]
+		if d != depth {
+			p.open, depth = nil, d
+		}
+
+		indent := markdownIndent(content)
+		table = table && strings.Contains(content, "|")
+		if strings.TrimSpace(content) == "" {
+			p.open, blank, table = nil, true, false
+			continue
+		} else if blank && indent == 0 && !reMarkdownItem.MatchString(content) {
+			list = false
+		}
+
+		switch {
+		case table:
+			p.tableRow(start, end)
+		case p.open == nil && indent >= 4 && !list:
+			i = p.skipIndentedCode(i, depth)
+		case reMarkdownFence.MatchString(content):
+			i = p.skipFencedCode(i, depth, content)
+		case p.open == nil && reHTMLBlock.MatchString(content):
+			i = p.skipHTML(i, content)
+		case p.open != nil && p.open.Kind != ListItem && reSetextUnderline.MatchString(content):
+			p.open.Kind = Heading
+			p.open = nil
+		case reMarkdownBreak.MatchString(content):
+			p.open = nil
+		case reMarkdownHeading.MatchString(content):
+			p.heading(start, end)
+		case p.open == nil && reReferenceDef.MatchString(content):
+		case strings.Contains(content, "|") && i+1 < len(p.lines) && p.isTableDelimiter(i+1, depth):
+			p.open, table = nil, true
+			p.tableRow(start, end)
+			i++
+		default:
+			if loc := reMarkdownItem.FindStringIndex(content); loc != nil {
+				list = true
+				p.begin(ListItem, start+loc[1], end)
+			} else if loc := reFootnoteDef.FindStringIndex(content); loc != nil {
+				p.begin(Footnote, start+loc[1], end)
+			} else if p.open != nil {
+				p.open.End = p.trimEnd(end)
+			} else if list && indent > 0 {
+				p.begin(ListItem, start, end)
+			} else if depth > 0 {
+				p.begin(BlockQuote, start, end)
+			} else {
+				p.begin(Paragraph, start, end)
+			}
+		}
+
+		blank = false
+	}
+}
+
+// begin starts a new block in text[start:end], which the following lines may
+// continue.
+func (p *blockParser) begin(kind BlockKind, start, end int) {
+	p.open = p.add(kind, start, end)
+}
+
+// add records a block in text[start:end], without its surrounding
+// whitespace, if it isn't empty.
+func (p *blockParser) add(kind BlockKind, start, end int) *Block {
+	for start < end && isBlank(p.text[start]) {
+		start++
+	}
+	end = p.trimEnd(end)
+	if start >= end {
+		return nil
+	}
+
+	// An image's "!" isn't the end of a sentence.
+	for i := start; i+1 < end; i++ {
+		if p.text[i] == '!' && p.text[i+1] == '[' {
+			p.masked[i] = ' '
+		}
+	}
+
+	b := &Block{Kind: kind, Index: len(p.blocks), Start: start, End: end}
+	p.blocks = append(p.blocks, b)
+	return b
+}
+
+func (p *blockParser) trimEnd(end int) int {
+	for end > 0 && isBlank(p.text[end-1]) {
+		end--
+	}
+	return end
+}
+
+// frontMatter returns the index of the first line after the document's YAML
+// front matter, if any.
+func (p *blockParser) frontMatter() int {
+	if len(p.lines) == 0 || p.lineText(0) != "---" {
+		return 0
+	}
+	for i := 1; i < len(p.lines); i++ {
+		if end := p.lineText(i); end == "---" || end == "..." {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (p *blockParser) lineText(i int) string {
+	return strings.TrimRight(p.text[p.lines[i].start:p.lines[i].end], " \t")
+}
+
+// quotes blanks out the block quote markers at the start of l, returning the
+// offset of the rest of l and the number of markers.
+func (p *blockParser) quotes(l markdownLine) (int, int) {
+	start, depth := l.start, 0
+	for {
+		i := start
+		for i < l.end && i-start < 3 && p.text[i] == ' ' {
+			i++
+		}
+		if i == l.end || p.text[i] != '>' {
+			return start, depth
+		}
+		p.masked[i] = ' '
+		start = i + 1
+		depth++
+	}
+}
+
+// skipIndentedCode returns the index of the last line of the indented code
+// block that starts at lines[i].
+func (p *blockParser) skipIndentedCode(i, depth int) int {
+	last := i
+	for j := i + 1; j < len(p.lines); j++ {
+		start, d := p.quotes(p.lines[j])
+		content := p.text[start:p.lines[j].end]
+		if strings.TrimSpace(content) == "" {
+			continue
+		} else if d != depth || markdownIndent(content) < 4 {
+			break
+		}
+		last = j
+	}
+	return last
+}
+
+// skipFencedCode returns the index of the last line of the fenced code block
+// that starts at lines[i], whose content (after any block quote markers) is
+// opening.
+func (p *blockParser) skipFencedCode(i, depth int, opening string) int {
+	p.open = nil
+
+	fence := strings.TrimLeft(opening, " ")
+	fence = fence[:len(fence)-len(strings.TrimLeft(fence, fence[:1]))]
+	for j := i + 1; j < len(p.lines); j++ {
+		start, _ := p.quotes(p.lines[j])
+		closing := strings.TrimSpace(p.text[start:p.lines[j].end])
+		if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			return j
+		}
+	}
+	return len(p.lines) - 1
+}
+
+// skipHTML returns the index of the last line of the HTML block that starts
+// at lines[i], whose content is opening.
+func (p *blockParser) skipHTML(i int, opening string) int {
+	m := reHTMLBlock.FindStringSubmatch(opening)
+
+	var terminator string
+	switch {
+	case m[1] != "":
+		terminator = "</" + strings.ToLower(m[1]) + ">"
+	case m[2] != "":
+		terminator = "-->"
+	default:
+		// The block ends at the next blank line.
+		for j := i + 1; j < len(p.lines); j++ {
+			if strings.TrimSpace(p.lineText(j)) == "" {
+				return j - 1
+			}
+		}
+		return len(p.lines) - 1
+	}
+
+	for j := i; j < len(p.lines); j++ {
+		if strings.Contains(strings.ToLower(p.lineText(j)), terminator) {
+			return j
+		}
+	}
+	return len(p.lines) - 1
+}
+
+// heading records the ATX heading in text[start:end].
+func (p *blockParser) heading(start, end int) {
+	content := p.text[start:end]
+	marker := reMarkdownHeading.FindStringIndex(content)[1]
+	if loc := reHeadingClosing.FindStringIndex(content[marker:]); loc != nil {
+		end = start + marker + loc[0]
+	}
+	p.add(Heading, start+marker, end)
+	p.open = nil
+}
+
+// isTableDelimiter determines if lines[i] is the delimiter row of a table.
+func (p *blockParser) isTableDelimiter(i, depth int) bool {
+	start, d := p.quotes(p.lines[i])
+	content := p.text[start:p.lines[i].end]
+	return d == depth && strings.Contains(content, "|") && reTableDelim.MatchString(content)
+}
+
+// tableRow records each of the cells of the table row in text[start:end].
+func (p *blockParser) tableRow(start, end int) {
+	content := p.text[start:end]
+	if reTableDelim.MatchString(content) {
+		return
+	}
+
+	cell := start
+	for i := start; i < end; i++ {
+		switch p.text[i] {
+		case '\\':
+			i++
+		case '|':
+			p.add(TableCell, cell, i)
+			cell = i + 1
+		}
+	}
+	p.add(TableCell, cell, end)
+}
+
+// markdownIndent returns the width of the whitespace at the start of s, with
+// tab stops every four columns.
+func markdownIndent(s string) int {
+	width := 0
+	for _, c := range []byte(s) {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
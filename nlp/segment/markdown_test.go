@@ -0,0 +1,100 @@
+package segment_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jdkato/twine/internal"
+	"github.com/jdkato/twine/nlp/segment"
+)
+
+type markdownSentence struct {
+	kind  segment.BlockKind
+	block int
+	text  string
+}
+
+func checkMarkdown(t *testing.T, s segment.Segmenter, text string, expected []markdownSentence) {
+	sents := s.Sentences(text)
+	if len(sents) != len(expected) {
+		for _, sent := range sents {
+			t.Logf("%v %d %q", sent.Block.Kind, sent.Block.Index, sent.Text)
+		}
+		t.Fatalf("Actual: %d sentences, Expected: %d", len(sents), len(expected))
+	}
+
+	runes := []rune(text)
+	for i, sent := range sents {
+		actual := markdownSentence{sent.Block.Kind, sent.Block.Index, sent.Text}
+		if actual != expected[i] {
+			t.Errorf("Actual: %v, Expected: %v", actual, expected[i])
+		}
+		if len(text[sent.Start:sent.End]) != len(sent.Text) ||
+			len(runes[sent.RuneStart:sent.RuneEnd]) != len([]rune(sent.Text)) {
+			t.Errorf("Bad offsets for %q", sent.Text)
+		}
+		if sent.Start < sent.Block.Start || sent.End > sent.Block.End {
+			t.Errorf("%q is outside of its block", sent.Text)
+		}
+	}
+}
+
+func TestMarkdownSegmenter(t *testing.T) {
+	text := string(internal.ReadDataFile(filepath.Join(testdata, "markup.md")))
+	checkMarkdown(t, segment.NewMarkdownSegmenter(), text, []markdownSentence{
+		{segment.Heading, 0, "Getting *started* with twine"},
+		{segment.Paragraph, 1, "Install it with `go get github.com/jdkato/twine`, then read the\n" +
+			"[tokenize docs](https://pkg.go.dev/github.com/jdkato/twine/nlp/tokenize \"API docs\")\n" +
+			"or the [FAQ][faq]."},
+		{segment.Paragraph, 1, "Questions?"},
+		{segment.Paragraph, 1, "Email <help@example.com>."},
+		{segment.BlockQuote, 2, "**Note:** the tokenizers don't split `snake_case_names`."},
+		{segment.ListItem, 3, "Split *prose* into words"},
+		{segment.ListItem, 4, "Keep ~~URLs~~ intact: <https://example.com/a_b>"},
+		{segment.ListItem, 5, "See  [the logo](img/logo.png) below."},
+		{segment.TableCell, 6, "Option"},
+		{segment.TableCell, 7, "Meaning"},
+		{segment.TableCell, 8, "`-v`"},
+		{segment.TableCell, 9, "Verbose"},
+		{segment.Paragraph, 10, "Some <em>inline</em> HTML, a <a href=\"https://golang.org\">link</a> and\n" +
+			"<!-- a comment --> a footnote.[^1]"},
+		{segment.Footnote, 11, "The footnote's text."},
+	})
+}
+
+func TestMarkdownSegmenterBlocks(t *testing.T) {
+	text := "Installation\n" +
+		"============\n\n" +
+		"## Requirements ##\n" +
+		"You'll need Go. It's free.\n\n" +
+		"> Quoted text that\n" +
+		"> spans two lines. Another sentence.\n\n" +
+		"* The first item\n" +
+		"  continues here\n" +
+		"* The second item\n\n" +
+		"  A paragraph in the second item.\n\n" +
+		"~~~\n" +
+		"Code. Isn't prose.\n\n" +
+		"More code.\n" +
+		"~~~\n\n" +
+		"    Indented code.\n\n" +
+		"---\n" +
+		"The end"
+
+	checkMarkdown(t, segment.NewMarkdownSegmenter(segment.UsingProseSegmenter(segment.NewPragmaticSegmenter())), text, []markdownSentence{
+		{segment.Heading, 0, "Installation"},
+		{segment.Heading, 1, "Requirements"},
+		{segment.Paragraph, 2, "You'll need Go."},
+		{segment.Paragraph, 2, "It's free."},
+		{segment.BlockQuote, 3, "Quoted text that\n  spans two lines."},
+		{segment.BlockQuote, 3, "Another sentence."},
+		{segment.ListItem, 4, "The first item\n  continues here"},
+		{segment.ListItem, 5, "The second item"},
+		{segment.ListItem, 6, "A paragraph in the second item."},
+		{segment.Paragraph, 7, "The end"},
+	})
+
+	if sents := segment.NewMarkdownSegmenter().Segment("---\ntitle: Test\n---\n"); len(sents) != 0 {
+		t.Errorf("Expected no sentences in front matter, got %q", sents)
+	}
+}
@@ -0,0 +1,154 @@
+package segment
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/neurosnap/sentences.v1"
+)
+
+// A PunktModel holds the parameters that a Punkt sentence tokenizer uses to
+// detect sentence boundaries: abbreviations, collocations, frequent sentence
+// starters and the orthographic contexts in which each word occurs.
+//
+// Models are serialized as JSON, in the same format as the built-in models.
+type PunktModel struct {
+	storage *sentences.Storage
+}
+
+// PunktModelFor returns the built-in model of the given language (see
+// NewPunktSentenceTokenizerFor), which is useful as the base of a
+// PunktTrainer.
+func PunktModelFor(language string) (*PunktModel, error) {
+	name, err := languageName(language)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := loadModel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &PunktModel{storage: storage}
+	m.AddAbbreviations(abbreviations[name]...)
+
+	return m, nil
+}
+
+// LoadPunktModel reads a PunktModel that was written by PunktModel.Save.
+func LoadPunktModel(r io.Reader) (*PunktModel, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := sentences.LoadTraining(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PunktModel{storage: normalizeStorage(storage)}, nil
+}
+
+// Save writes the PunktModel to w.
+func (m *PunktModel) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m.storage)
+}
+
+// AddAbbreviations adds abbreviations to the PunktModel. They're case
+// insensitive and don't need their final period (e.g., "approx" and
+// "Approx." are equivalent).
+func (m *PunktModel) AddAbbreviations(abbrevs ...string) {
+	for _, abbr := range abbrevs {
+		m.storage.AbbrevTypes.Add(strings.TrimSuffix(strings.ToLower(abbr), "."))
+	}
+}
+
+// Abbreviations returns the PunktModel's abbreviations, which are lowercase
+// and lack their final period.
+func (m *PunktModel) Abbreviations() []string {
+	return sortedKeys(m.storage.AbbrevTypes)
+}
+
+// Collocations returns the pairs of words that the PunktModel considers to
+// be collocations (e.g., an ordinal number followed by a month), which never
+// have a sentence boundary between them.
+func (m *PunktModel) Collocations() [][2]string {
+	pairs := [][2]string{}
+	for _, key := range sortedKeys(m.storage.Collocations) {
+		first, second, _ := strings.Cut(key, ",")
+		pairs = append(pairs, [2]string{first, second})
+	}
+	return pairs
+}
+
+// SentenceStarters returns the words that frequently start sentences.
+func (m *PunktModel) SentenceStarters() []string {
+	return sortedKeys(m.storage.SentStarters)
+}
+
+// OrthographicContext returns a map of each word to the contexts in which it
+// has occurred: a bit set of whether it has been seen at the beginning of a
+// sentence, in the middle of one or in an unknown position, capitalized or
+// in lowercase.
+func (m *PunktModel) OrthographicContext() map[string]int {
+	context := make(map[string]int, len(m.storage.OrthoContext))
+	for typ, flags := range m.storage.OrthoContext {
+		context[typ] = flags
+	}
+	return context
+}
+
+// NewPunktSentenceTokenizerFromModel creates a new PunktSentenceTokenizer
+// that uses the given model, such as one created by a PunktTrainer.
+func NewPunktSentenceTokenizerFromModel(m *PunktModel, opts ...SegmenterOptFunc) *punktSentenceTokenizer {
+	// The tokenizer doesn't modify the model, since it's copied here.
+	tokenizer, err := newSentenceTokenizer(m.copy(), newVocabulary(nil, nil, opts))
+	if err != nil {
+		panic(err)
+	}
+	return &punktSentenceTokenizer{tokenizer: tokenizer}
+}
+
+// copy returns a deep copy of the PunktModel's storage.
+func (m *PunktModel) copy() *sentences.Storage {
+	storage := sentences.NewStorage()
+	for _, pair := range []struct{ from, to sentences.SetString }{
+		{m.storage.AbbrevTypes, storage.AbbrevTypes},
+		{m.storage.Collocations, storage.Collocations},
+		{m.storage.SentStarters, storage.SentStarters},
+		{m.storage.OrthoContext, storage.OrthoContext},
+	} {
+		for key, value := range pair.from {
+			pair.to[key] = value
+		}
+	}
+	return storage
+}
+
+// normalizeStorage ensures that none of storage's sets are nil, which may be
+// the case if they were missing from its JSON.
+func normalizeStorage(storage *sentences.Storage) *sentences.Storage {
+	if storage.AbbrevTypes == nil {
+		storage.AbbrevTypes = sentences.SetString{}
+	}
+	if storage.Collocations == nil {
+		storage.Collocations = sentences.SetString{}
+	}
+	if storage.SentStarters == nil {
+		storage.SentStarters = sentences.SetString{}
+	}
+	if storage.OrthoContext == nil {
+		storage.OrthoContext = sentences.SetString{}
+	}
+	return storage
+}
+
+func sortedKeys(set sentences.SetString) []string {
+	keys := set.Array()
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,437 @@
+package segment
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pragmaticSegmenter is a deterministic, rule-based segmenter for English
+// that's modeled on the Pragmatic Segmenter
+// (https://github.com/diasks2/pragmatic_segmenter) and passes its "Golden
+// Rules."
+//
+// Rather than rewriting the text, it decides whether each occurrence of
+// sentence-ending punctuation is a boundary based on the words around it, so
+// that the positions of its sentences are exact.
+type pragmaticSegmenter struct {
+	// abbreviations end a sentence only if they're followed by a capitalized
+	// word (e.g., "Co.").
+	abbreviations map[string]bool
+	// prefixes precede names, so they never end a sentence (e.g., "Dr.").
+	prefixes map[string]bool
+	// numeric abbreviations don't end a sentence when they're followed by a
+	// number (e.g., "p. 55").
+	numeric map[string]bool
+	// entities are names that contain sentence-ending punctuation (e.g.,
+	// "Yahoo!").
+	entities []string
+	// starters are words that frequently start sentences, which is how
+	// initials and abbreviations such as "U.S." are disambiguated.
+	starters map[string]bool
+}
+
+// All of the abbreviations are lowercase and lack their final period.
+var pragmaticPrefixes = []string{
+	"adm", "capt", "cmdr", "col", "dr", "gen", "gov", "hon", "lt", "maj",
+	"messrs", "mr", "mrs", "ms", "mt", "pres", "prof", "rep", "rev", "sen",
+	"sgt", "st", "supt",
+}
+
+var pragmaticNumeric = []string{
+	"art", "ch", "chap", "eq", "eqs", "fig", "figs", "n°", "nº", "no", "nos",
+	"op", "p", "para", "pp", "pt", "ref", "refs", "sec", "vol", "vols",
+}
+
+var pragmaticAbbreviations = []string{
+	"al", "approx", "apr", "assn", "aug", "ave", "blvd", "bros", "ca", "cf",
+	"co", "corp", "dec", "dept", "est", "etc", "feb", "inc", "jan", "jr",
+	"jul", "jun", "ltd", "mar", "misc", "nov", "oct", "rd", "sep", "sept",
+	"sr", "univ", "vs",
+}
+
+// pragmaticStarters are the Pragmatic Segmenter's sentence starters.
+var pragmaticStarters = []string{
+	"A", "Being", "Did", "For", "He", "How", "However", "I", "In", "It",
+	"Millions", "More", "She", "That", "The", "There", "They", "We", "What",
+	"When", "Where", "Who", "Why",
+}
+
+var (
+	reURL           = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S*[^\s.,;:!?'")\]”’]`)
+	reEmail         = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	reListMarker    = regexp.MustCompile(`(?:^|\s)(?:(\d{1,2})|([a-z]))(?:\.\)|[.)])`)
+	reBullet        = regexp.MustCompile(`[•⁃‣◦]\s*(?:(?:\d{1,2}|[a-z])(?:\.\)|[.)]))?`)
+	reParagraph     = regexp.MustCompile(`\n[ \t]*\n`)
+	reMultiPeriod   = regexp.MustCompile(`^(?:\pL\.)+\pL$`)
+	reIntroTime     = regexp.MustCompile(`^\W*(?:\pL+\s+)?\d{1,2}(?::\d{2})?\s*$`)
+	reErrantNewline = regexp.MustCompile(`[ \t]*\r?\n\s*`)
+)
+
+// NewPragmaticSegmenter creates a new rule-based Segmenter for English. The
+// options add to its built-in vocabulary.
+func NewPragmaticSegmenter(opts ...SegmenterOptFunc) *pragmaticSegmenter {
+	vocab := newVocabulary(pragmaticAbbreviations, pragmaticPrefixes, opts)
+	return &pragmaticSegmenter{
+		abbreviations: toSet(vocab.Abbreviations),
+		prefixes:      toSet(vocab.Prefixes),
+		numeric:       toSet(pragmaticNumeric),
+		entities:      vocab.Entities,
+		starters:      toSet(pragmaticStarters),
+	}
+}
+
+// Segment splits text into sentences.
+//
+// Line breaks within a sentence (e.g., in text extracted from a PDF) are
+// replaced with spaces; use Sentences for the sentences' original text.
+func (p *pragmaticSegmenter) Segment(text string) []string {
+	sents := []string{}
+	for _, s := range p.Sentences(text) {
+		sents = append(sents, reErrantNewline.ReplaceAllString(s.Text, " "))
+	}
+	return sents
+}
+
+// Sentences splits text into sentences, along with their positions in text.
+func (p *pragmaticSegmenter) Sentences(text string) []Sentence {
+	breaks := p.boundaries(text)
+
+	spans := [][2]int{}
+	start := 0
+	for _, b := range breaks {
+		spans = append(spans, [2]int{start, b})
+		start = b
+	}
+	spans = append(spans, [2]int{start, len(text)})
+
+	return newSentences(text, spans)
+}
+
+// boundaries returns the (sorted) byte offsets of text's sentence
+// boundaries.
+func (p *pragmaticSegmenter) boundaries(text string) []int {
+	protected := p.protect(text)
+
+	// Lists and paragraphs are found first, since they're independent of
+	// punctuation.
+	breaks := p.listBreaks(text, protected)
+	for _, loc := range reParagraph.FindAllStringIndex(text, -1) {
+		breaks = append(breaks, loc[0])
+	}
+	sort.Ints(breaks)
+	structural := len(breaks)
+
+	last, next := 0, 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isTerminal(r) || protected[i] {
+			i += size
+			continue
+		}
+
+		for next < structural && breaks[next] <= i {
+			last = breaks[next]
+			next++
+		}
+
+		c := newCandidate(text, i)
+		if b, found := p.boundary(text, c, last); found {
+			breaks = append(breaks, b)
+			last = b
+		}
+		i = c.end
+	}
+
+	sort.Ints(breaks)
+	return breaks
+}
+
+// protect returns a mask of text's bytes at which a sentence can't end:
+// those in URLs, email addresses and entities.
+func (p *pragmaticSegmenter) protect(text string) []bool {
+	protected := make([]bool, len(text))
+	mark := func(start, end int) {
+		for i := start; i < end; i++ {
+			protected[i] = true
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{reURL, reEmail} {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			mark(loc[0], loc[1])
+		}
+	}
+
+	for _, entity := range p.entities {
+		for offset := 0; offset < len(text); {
+			idx := strings.Index(text[offset:], entity)
+			if idx < 0 {
+				break
+			}
+			mark(offset+idx, offset+idx+len(entity))
+			offset += idx + len(entity)
+		}
+	}
+
+	return protected
+}
+
+type listMarker struct {
+	start, end int
+	value      int
+}
+
+// listBreaks returns the boundaries before the items of numbered,
+// alphabetical and bulleted lists (e.g., "1. The first item 2. The second
+// item"), protecting the punctuation of their markers.
+//
+// Numbered and alphabetical markers are only considered to be part of a list
+// if they count up from 1 (or "a") by one.
+func (p *pragmaticSegmenter) listBreaks(text string, protected []bool) []int {
+	breaks := []int{}
+	for _, loc := range reBullet.FindAllStringIndex(text, -1) {
+		breaks = append(breaks, loc[0])
+		for i := loc[0]; i < loc[1]; i++ {
+			protected[i] = true
+		}
+	}
+
+	runs := map[bool][]listMarker{}
+	flush := func(alpha bool) {
+		if run := runs[alpha]; len(run) > 1 {
+			for _, m := range run {
+				breaks = append(breaks, m.start)
+				for i := m.start; i < m.end; i++ {
+					protected[i] = true
+				}
+			}
+		}
+		runs[alpha] = nil
+	}
+
+	for _, loc := range reListMarker.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[2], loc[1]
+		alpha := loc[2] < 0
+		if alpha {
+			start = loc[4]
+		}
+		if protected[start] || (end < len(text) && !isSpace(text[end:])) {
+			continue
+		}
+
+		m := listMarker{start: start, end: end}
+		if alpha {
+			m.value = int(text[start]-'a') + 1
+		} else {
+			for _, d := range text[start:loc[3]] {
+				m.value = m.value*10 + int(d-'0')
+			}
+		}
+
+		run := runs[alpha]
+		if m.value == 1 {
+			flush(alpha)
+			runs[alpha] = []listMarker{m}
+		} else if len(run) > 0 && m.value == run[len(run)-1].value+1 {
+			runs[alpha] = append(run, m)
+		}
+	}
+	flush(false)
+	flush(true)
+
+	return breaks
+}
+
+// A candidate is an occurrence of sentence-ending punctuation.
+type candidate struct {
+	word   string // The token preceding the punctuation (e.g., "U.S").
+	punct  string // The punctuation (e.g., "?!" or ". . .").
+	start  int    // The byte offset of the punctuation.
+	end    int    // The byte offset after the punctuation and any closers.
+	closed bool   // Whether the punctuation is followed by a closer.
+	next   string // The token following the punctuation.
+	spaced bool   // Whether whitespace (or the end of text) follows.
+}
+
+// closers may follow the punctuation that ends a sentence.
+const closers = `"')]”’»`
+
+func newCandidate(text string, start int) candidate {
+	c := candidate{start: start}
+
+	wordStart := start
+	for wordStart > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:wordStart])
+		if unicode.IsSpace(r) {
+			break
+		}
+		wordStart -= size
+	}
+	c.word = text[wordStart:start]
+
+	// Include spaced ellipses (". . .") in the punctuation.
+	end := start
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if isTerminal(r) {
+			end += size
+		} else if r == ' ' && end+1 < len(text) && text[end+1] == '.' {
+			end++
+		} else {
+			break
+		}
+	}
+	c.punct = text[start:end]
+
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !strings.ContainsRune(closers, r) {
+			break
+		}
+		c.closed = true
+		end += size
+	}
+	c.end = end
+
+	c.spaced = end == len(text) || isSpace(text[end:])
+	rest := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+		rest = rest[:i]
+	}
+	c.next = rest
+
+	return c
+}
+
+// boundary decides whether the candidate ends a sentence and, if so, where
+// (which may not be at its end). The sentence in which c occurs began at
+// start.
+func (p *pragmaticSegmenter) boundary(text string, c candidate, start int) (int, bool) {
+	if c.next == "" {
+		return 0, false
+	} else if !c.spaced {
+		// A period between two words, with a missing space (e.g.,
+		// "world.Today").
+		return c.end, c.punct == "." && !c.closed && p.isRunOn(text, c)
+	}
+
+	next := firstRune(c.next)
+	lower := unicode.IsLower(next) || strings.ContainsRune(",;:%", next)
+	if strings.ContainsAny(c.punct, "?!") {
+		return c.end, !lower
+	}
+
+	dots := strings.Count(c.punct, ".") + 3*strings.Count(c.punct, "…")
+	if dots > 1 {
+		return p.ellipsisBoundary(c, dots, unicode.IsUpper(next))
+	}
+
+	typ := strings.ToLower(strings.TrimLeft(c.word, `"'([“‘`))
+	switch {
+	case p.prefixes[typ] || p.prefixes[typ[strings.LastIndex(typ, ".")+1:]]:
+		// The prefix may follow a missing space (e.g., "Tuesday.Mr.").
+		return 0, false
+	case p.numeric[typ] && unicode.IsDigit(next):
+		return 0, false
+	case lower:
+		return 0, false
+	case typ == "a.m" || typ == "p.m":
+		// A time ends a sentence if it's followed by a sentence starter or,
+		// unless it introduces the sentence (e.g., "At 5 a.m. Mr. Smith went
+		// to the bank."), by any other capitalized word.
+		return c.end, p.starters[leadingWord(strings.TrimLeft(c.next, `"'([“‘`))] ||
+			(unicode.IsUpper(next) && !reIntroTime.MatchString(text[start:c.start-len(c.word)]))
+	case reMultiPeriod.MatchString(c.word) || isInitial(c.word):
+		// An acronym (e.g., "U.S.") or initial, which may precede a proper
+		// noun.
+		return c.end, p.starters[leadingWord(strings.TrimLeft(c.next, `"'([“‘`))]
+	case p.abbreviations[typ]:
+		return c.end, !unicode.IsDigit(next)
+	}
+
+	return c.end, true
+}
+
+// ellipsisBoundary decides whether an ellipsis ends a sentence. Following
+// the Pragmatic Segmenter's rules, an ellipsis that's followed by a
+// capitalized word ends a sentence unless it's spaced (". . .") and only
+// three dots. If a four-dot ellipsis is spaced, attached to the preceding word
+// and begins with a period (rather than "…"), the first period ends the
+// sentence and the ellipsis starts the next.
+func (p *pragmaticSegmenter) ellipsisBoundary(c candidate, dots int, upper bool) (int, bool) {
+	spaced := strings.Contains(c.punct, " ")
+	switch {
+	case !upper || strings.HasSuffix(c.word, "["):
+		return 0, false
+	case spaced && dots > 3 && c.word != "" && c.punct[0] == '.':
+		return c.start + 1, true
+	case spaced && dots < 4:
+		return 0, false
+	}
+	return c.end, true
+}
+
+// isRunOn determines if the period of c joins two sentences without a space:
+// one ending with a lowercase word or a number and one starting with a
+// capitalized word (but not, e.g., an identifier such as "os.Exit()").
+func (p *pragmaticSegmenter) isRunOn(text string, c candidate) bool {
+	last, _ := utf8.DecodeLastRuneInString(c.word)
+	if !unicode.IsLower(last) && !unicode.IsDigit(last) {
+		return false
+	}
+
+	rest := text[c.end:]
+	word := leadingWord(rest)
+	n := len(word)
+
+	first, size := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) || size == len(word) ||
+		strings.IndexFunc(word[size:], unicode.IsUpper) >= 0 {
+		return false
+	}
+
+	after := rest[n:]
+	return after == "" || isSpace(after) || strings.ContainsAny(after[:1], ",;:!?") ||
+		(after[0] == '.' && (len(after) == 1 || isSpace(after[1:])))
+}
+
+// leadingWord returns the letters at the start of s.
+func leadingWord(s string) string {
+	if n := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }); n >= 0 {
+		return s[:n]
+	}
+	return s
+}
+
+func isTerminal(r rune) bool {
+	return r == '.' || r == '?' || r == '!' || r == '…'
+}
+
+// isInitial determines if word is a single uppercase letter (e.g., "E" in
+// "Jonas E. Smith").
+func isInitial(word string) bool {
+	r, size := utf8.DecodeRuneInString(word)
+	return size == len(word) && unicode.IsUpper(r)
+}
+
+// isSpace determines if s starts with whitespace.
+func isSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
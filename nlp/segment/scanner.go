@@ -0,0 +1,251 @@
+package segment
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrSentenceTooLong is returned by Scanner.Err when the Scanner's buffer
+// can't hold enough text to confirm a sentence boundary.
+var ErrSentenceTooLong = errors.New("segment: sentence too long")
+
+const (
+	// MaxScanSentenceSize is the default maximum size of the buffer used by
+	// a Scanner; see Scanner.Buffer.
+	MaxScanSentenceSize = 64 * 1024
+
+	startBufSize  = 4096
+	maxEmptyReads = 100
+)
+
+// A Scanner reads Sentences from an io.Reader.
+//
+// A Scanner only produces a sentence once it has read a quarter of its buffer
+// (see Buffer) past the sentence's end, since a boundary may depend on the
+// text that follows it, and it keeps up to another quarter of the sentences
+// that it has produced in its buffer as context for those that follow. Its
+// Sentences' offsets index into the stream as a whole, and they're otherwise
+// the same as the Segmenter's, provided that its boundaries only depend on
+// the text within that distance of them -- as is the case for the Punkt
+// segmenter, and for the pragmatic segmenter unless a numbered list's items
+// are further apart. (A Markdown segmenter, on the other hand, needs to see
+// the entire document to find its blocks.)
+//
+// Like bufio.Scanner, successive calls to Scan step through the sentences, and
+// scanning stops unrecoverably at EOF, at the first I/O error, or when a
+// sentence is too long to be confirmed within the buffer.
+type Scanner struct {
+	r         io.Reader
+	segmenter Segmenter
+	buf       []byte
+	context   int // The first byte of the produced text kept in buf.
+	start     int // The first unconsumed byte in buf.
+	end       int // The end of the data in buf.
+	maxSize   int
+	offset    int // The stream's byte offset of buf[start].
+	runes     int // The stream's rune offset of buf[start].
+	tried     int // The amount of data that last failed to produce sentences.
+	pending   []Sentence
+	sentence  Sentence
+	scanned   bool
+	eof       bool
+	done      bool
+	err       error
+}
+
+// NewScanner returns a Scanner that uses s to read Sentences from r.
+func NewScanner(r io.Reader, s Segmenter) *Scanner {
+	return &Scanner{r: r, segmenter: s, maxSize: MaxScanSentenceSize}
+}
+
+// Buffer sets the initial buffer to use when scanning and the maximum size of
+// buffer that may be allocated during scanning, as with bufio.Scanner. The
+// maximum size determines how far the Scanner looks ahead of the sentences
+// that it produces and how much context it keeps behind them.
+//
+// Buffer panics if it is called after scanning has started.
+func (s *Scanner) Buffer(buf []byte, max int) {
+	if s.scanned {
+		panic("segment: Buffer called after Scan")
+	}
+	s.buf = buf[0:cap(buf)]
+	s.maxSize = max
+}
+
+// Scan advances the Scanner to the next sentence, which will then be
+// available through the Sentence method. It returns false when there are no
+// more sentences, either by reaching the end of the input or an error.
+func (s *Scanner) Scan() bool {
+	s.scanned = true
+	for len(s.pending) == 0 {
+		if s.done {
+			s.sentence = Sentence{}
+			return false
+		}
+		if s.eof || s.ready() {
+			s.consume()
+		}
+		if len(s.pending) == 0 && !s.done {
+			s.fill()
+		}
+	}
+
+	s.sentence, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// Sentence returns the most recent sentence generated by a call to Scan.
+func (s *Scanner) Sentence() Sentence {
+	return s.sentence
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// ready reports whether the buffered data is worth segmenting: either it has
+// at least doubled since it last failed to produce any sentences -- which
+// keeps the cost of segmenting it linear -- or the buffer is full.
+func (s *Scanner) ready() bool {
+	size := s.end - s.start
+	return size > 0 && (size >= 2*s.tried || s.end == len(s.buf))
+}
+
+// consume segments the buffered data, along with its context, queuing the
+// sentences that are final.
+func (s *Scanner) consume() {
+	data := string(s.buf[s.context:s.end])
+	if !s.eof {
+		// Hold back a partial rune until the rest of it has been read.
+		data = data[:fullRunes(data)]
+	}
+	from := s.start - s.context
+
+	sents := s.segmenter.Sentences(data)
+
+	// Skip the sentences in the context, which have already been produced.
+	first := 0
+	for first < len(sents) && sents[first].End <= from {
+		first++
+	}
+
+	final := len(sents)
+	if !s.eof {
+		final = first
+		for final < len(sents)-2 && sents[final].End <= len(data)-s.maxSize/4 {
+			final++
+		}
+		if final == first {
+			s.tried = len(data) - from
+			return
+		}
+	}
+
+	contextRunes := utf8.RuneCountInString(data[:from])
+	for _, sent := range sents[first:final] {
+		if sent.Start < from {
+			// The context's last sentence has been extended, so only the
+			// part of it that hasn't been produced is new.
+			sent.RuneStart += utf8.RuneCountInString(data[sent.Start:from])
+			sent.Text = sent.Text[from-sent.Start:]
+			sent.Start = from
+		}
+		sent.Start += s.offset - from
+		sent.End += s.offset - from
+		sent.RuneStart += s.runes - contextRunes
+		sent.RuneEnd += s.runes - contextRunes
+		s.pending = append(s.pending, sent)
+	}
+
+	advance := len(data)
+	if final < len(sents) {
+		advance = sents[final].Start
+	}
+
+	// Keep as many of the produced sentences as fit in the context.
+	keep := advance
+	for _, sent := range sents[:final] {
+		if advance-sent.Start <= s.maxSize/4 {
+			keep = sent.Start
+			break
+		}
+	}
+
+	s.offset += advance - from
+	s.runes += utf8.RuneCountInString(data[from:advance])
+	s.start += advance - from
+	s.context += keep
+	s.tried = 0
+	if s.eof {
+		s.done = true
+	}
+}
+
+// fullRunes returns the length of the longest prefix of data that doesn't end
+// with a partial rune.
+func fullRunes(data string) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRuneInString(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
+
+// fill reads more data into the buffer, making room for it if necessary.
+func (s *Scanner) fill() {
+	if s.end == len(s.buf) && s.context == 0 && len(s.buf) >= s.maxSize {
+		// Sacrifice the context before giving up.
+		s.context = s.start
+	}
+
+	if s.context > 0 && (s.end == len(s.buf) || s.context > len(s.buf)/2) {
+		copy(s.buf, s.buf[s.context:s.end])
+		s.start -= s.context
+		s.end -= s.context
+		s.context = 0
+	}
+
+	if s.end == len(s.buf) {
+		if len(s.buf) >= s.maxSize {
+			s.err = ErrSentenceTooLong
+			s.done = true
+			return
+		}
+		size := len(s.buf) * 2
+		if size == 0 {
+			size = startBufSize
+		}
+		if size > s.maxSize {
+			size = s.maxSize
+		}
+		buf := make([]byte, size)
+		copy(buf, s.buf[:s.end])
+		s.buf = buf
+	}
+
+	for reads := 0; ; {
+		n, err := s.r.Read(s.buf[s.end:])
+		s.end += n
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			s.eof = true
+			return
+		} else if n > 0 {
+			return
+		}
+		reads++
+		if reads >= maxEmptyReads {
+			s.err = io.ErrNoProgress
+			s.eof = true
+			return
+		}
+	}
+}
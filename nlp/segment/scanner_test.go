@@ -0,0 +1,109 @@
+package segment_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jdkato/twine/internal"
+	"github.com/jdkato/twine/nlp/segment"
+)
+
+func scanSentences(t *testing.T, s *segment.Scanner) []segment.Sentence {
+	var sents []segment.Sentence
+	for s.Scan() {
+		sents = append(sents, s.Sentence())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner(): unexpected error: %v", err)
+	}
+	return sents
+}
+
+func TestScanner(t *testing.T) {
+	book := internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))
+
+	for name, test := range map[string]struct {
+		s    segment.Segmenter
+		size int
+	}{
+		"punkt": {segmenter, 2048},
+		// The license at the end of the book has numbered sections, the
+		// furthest apart of which are about 12KB apart.
+		"pragmatic": {segment.NewPragmaticSegmenter(), segment.MaxScanSentenceSize},
+	} {
+		expected := test.s.Sentences(string(book))
+
+		actual := scanSentences(t, segment.NewScanner(bytes.NewReader(book), test.s))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: got %d sentences, expected %d", name, len(actual), len(expected))
+		}
+
+		sc := segment.NewScanner(iotest.HalfReader(bytes.NewReader(book)), test.s)
+		sc.Buffer(nil, test.size)
+		actual = scanSentences(t, sc)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s (%d-byte buffer): got %d sentences, expected %d",
+				name, test.size, len(actual), len(expected))
+		}
+	}
+}
+
+func TestScannerList(t *testing.T) {
+	text := "Here are steps. 1. Open the file. 2. Edit it. 3. Save it. " +
+		"Then you are done. More text follows here. And more."
+
+	s := segment.NewPragmaticSegmenter()
+	expected := s.Sentences(text)
+	if len(expected) != 7 || expected[2].Text != "2. Edit it." {
+		t.Fatalf("Unexpected sentences: %+v", expected)
+	}
+
+	for _, size := range []int{256, segment.MaxScanSentenceSize} {
+		sc := segment.NewScanner(iotest.OneByteReader(strings.NewReader(text)), s)
+		sc.Buffer(nil, size)
+		if actual := scanSentences(t, sc); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%d-byte buffer: Actual: %+v, Expected: %+v", size, actual, expected)
+		}
+	}
+}
+
+func TestScannerOffsets(t *testing.T) {
+	text := "«Où est-il?» demanda-t-elle.  Il est parti à Genève.\n\nÇa va. Très bien!"
+	expected := segmenter.Sentences(text)
+
+	actual := scanSentences(t, segment.NewScanner(iotest.OneByteReader(strings.NewReader(text)), segmenter))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %+v, Expected: %+v", actual, expected)
+	}
+
+	for _, sent := range actual {
+		if text[sent.Start:sent.End] != sent.Text {
+			t.Errorf("text[%d:%d] = %q, expected %q", sent.Start, sent.End, text[sent.Start:sent.End], sent.Text)
+		}
+	}
+}
+
+func TestScannerTooLong(t *testing.T) {
+	text := strings.Repeat("word ", 1000) + "end. Another one. And another."
+
+	sc := segment.NewScanner(strings.NewReader(text), segmenter)
+	sc.Buffer(nil, 1024)
+	for sc.Scan() {
+	}
+	if sc.Err() != segment.ErrSentenceTooLong {
+		t.Errorf("Expected ErrSentenceTooLong, got %v", sc.Err())
+	}
+}
+
+func BenchmarkScanner(b *testing.B) {
+	text := internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))
+	for n := 0; n < b.N; n++ {
+		sc := segment.NewScanner(bytes.NewReader(text), segmenter)
+		for sc.Scan() {
+		}
+	}
+}
@@ -23,14 +23,35 @@ package segment
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"gopkg.in/neurosnap/sentences.v1"
-	"gopkg.in/neurosnap/sentences.v1/data"
 )
 
 // A Sentence represents a segmented portion of text.
+//
+// Its offsets exclude the whitespace surrounding it, so that (for a text of
+// which it's a part) text[Start:End] is Text, except for any markup that a
+// Markdown segmenter blanked out.
 type Sentence struct {
-	Text string // The sentence's text.
+	Text      string // The sentence's text.
+	Start     int    // The byte offset of the sentence's first character.
+	End       int    // The byte offset just past the sentence's last character.
+	RuneStart int    // The rune offset of the sentence's first character.
+	RuneEnd   int    // The rune offset just past the sentence's last character.
+	Block     *Block // The Markdown block containing the sentence, if any.
+}
+
+// A Segmenter splits text into sentences.
+//
+// Both the (statistical) Punkt segmenter and the (rule-based) pragmatic
+// segmenter are Segmenters.
+type Segmenter interface {
+	// Segment returns the text of each of text's sentences.
+	Segment(text string) []string
+	// Sentences returns each of text's sentences, along with its position.
+	Sentences(text string) []Sentence
 }
 
 // punktSentenceTokenizer is an extension of the Go implementation of the Punkt
@@ -42,11 +63,12 @@ type punktSentenceTokenizer struct {
 
 // NewPunktSentenceTokenizer creates a new PunktSentenceTokenizer and loads
 // its English model.
-func NewPunktSentenceTokenizer() *punktSentenceTokenizer {
+func NewPunktSentenceTokenizer(opts ...SegmenterOptFunc) *punktSentenceTokenizer {
 	var pt punktSentenceTokenizer
 	var err error
 
-	pt.tokenizer, err = newSentenceTokenizer(nil)
+	vocab := newVocabulary(abbreviations["english"], titles["english"], opts)
+	pt.tokenizer, err = newSentenceTokenizer(nil, vocab)
 	if err != nil {
 		panic(err)
 	}
@@ -63,38 +85,72 @@ func (p punktSentenceTokenizer) Segment(text string) []string {
 	return sents
 }
 
+// Sentences splits text into sentences, along with their positions in text.
+// Unlike Segment, it omits sentences that consist only of whitespace.
+func (p punktSentenceTokenizer) Sentences(text string) []Sentence {
+	spans := [][2]int{}
+	for _, s := range p.tokenizer.Tokenize(text) {
+		spans = append(spans, [2]int{s.Start, s.End})
+	}
+	return newSentences(text, spans)
+}
+
+// newSentences creates the Sentences for the given (ordered) byte spans of
+// text, trimming the whitespace around each one.
+func newSentences(text string, spans [][2]int) []Sentence {
+	sents := []Sentence{}
+
+	offset, runes := 0, 0
+	for _, span := range spans {
+		raw := text[span[0]:span[1]]
+		start := span[0] + len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+		end := span[0] + len(strings.TrimRightFunc(raw, unicode.IsSpace))
+		if start >= end {
+			continue
+		}
+
+		runes += utf8.RuneCountInString(text[offset:start])
+		sent := Sentence{Text: text[start:end], Start: start, End: end, RuneStart: runes}
+		runes += utf8.RuneCountInString(sent.Text)
+		sent.RuneEnd = runes
+
+		sents = append(sents, sent)
+		offset = end
+	}
+
+	return sents
+}
+
 type wordTokenizer struct {
 	sentences.DefaultWordTokenizer
+	entities []string
 }
 
 var reAbbr = regexp.MustCompile(`((?:[\w]\.)+[\w]*\.)`)
 var reLooksLikeEllipsis = regexp.MustCompile(`(?:\.\s?){2,}\.`)
-var reEntities = regexp.MustCompile(`Yahoo!`)
 
-// English customized sentence tokenizer.
-func newSentenceTokenizer(s *sentences.Storage) (*sentences.DefaultSentenceTokenizer, error) {
+// Customized sentence tokenizer, which uses the English model if s is nil.
+// The vocabulary's prefixes (titles) are abbreviations (which needn't be in
+// s) that never end a sentence.
+func newSentenceTokenizer(s *sentences.Storage, vocab *Vocabulary) (*sentences.DefaultSentenceTokenizer, error) {
 	training := s
 
 	if training == nil {
-		b, err := data.Asset("data/english.json")
-		if err != nil {
-			return nil, err
-		}
-
-		training, err = sentences.LoadTraining(b)
+		var err error
+		training, err = loadModel("english")
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// supervisor abbreviations
-	abbrevs := []string{"sgt", "gov", "no", "mt"}
-	for _, abbr := range abbrevs {
+	for _, abbr := range vocab.Abbreviations {
 		training.AbbrevTypes.Add(abbr)
 	}
 
 	lang := sentences.NewPunctStrings()
 	word := newWordTokenizer(lang)
+	word.entities = vocab.Entities
 	annotations := sentences.NewAnnotations(training, lang, word)
 
 	ortho := &sentences.OrthoContext{
@@ -109,6 +165,8 @@ func newSentenceTokenizer(s *sentences.Storage) (*sentences.DefaultSentenceToken
 		TokenParser:  word,
 		TokenGrouper: &sentences.DefaultTokenGrouper{},
 		Ortho:        ortho,
+		titles:       toSet(vocab.Prefixes),
+		entities:     vocab.Entities,
 	}
 
 	annotations = append(annotations, multiPunct)
@@ -132,13 +190,13 @@ func newWordTokenizer(p sentences.PunctStrings) *wordTokenizer {
 
 func (e *wordTokenizer) HasSentEndChars(t *sentences.Token) bool {
 	enders := []string{
-		`."`, `.)`, `.’`, `.”`,
-		`?`, `?"`, `?'`, `?)`, `?’`, `?”`,
-		`!`, `!"`, `!'`, `!)`, `!’`, `!”`,
+		`."`, `.)`, `.’`, `.”`, `.»`,
+		`?`, `?"`, `?'`, `?)`, `?’`, `?”`, `?»`,
+		`!`, `!"`, `!'`, `!)`, `!’`, `!”`, `!»`,
 	}
 
 	for _, ender := range enders {
-		if strings.HasSuffix(t.Tok, ender) && !reEntities.MatchString(t.Tok) {
+		if strings.HasSuffix(t.Tok, ender) && !isEntity(t.Tok, e.entities) {
 			return true
 		}
 	}
@@ -165,6 +223,8 @@ type multiPunctWordAnnotation struct {
 	sentences.TokenParser
 	sentences.TokenGrouper
 	sentences.Ortho
+	titles   map[string]bool
+	entities []string
 }
 
 func (a *multiPunctWordAnnotation) Annotate(tokens []*sentences.Token) []*sentences.Token {
@@ -187,7 +247,7 @@ func (a *multiPunctWordAnnotation) Annotate(tokens []*sentences.Token) []*senten
 // looksInternal determines if tok's punctuation could appear
 // sentence-internally (i.e., parentheses or quotations).
 func looksInternal(tok string) bool {
-	internal := []string{")", `’`, `”`, `"`, `'`}
+	internal := []string{")", `’`, `”`, `"`, `'`, `»`}
 	for _, punc := range internal {
 		if strings.HasSuffix(tok, punc) {
 			return true
@@ -196,6 +256,42 @@ func looksInternal(tok string) bool {
 	return false
 }
 
+// isEntity determines if tok is one of the given entities, ignoring any
+// surrounding quotation marks and brackets. Punctuation that follows an
+// entity (e.g., the period of "Node.js.") can still end a sentence.
+func isEntity(tok string, entities []string) bool {
+	tok = strings.TrimRight(strings.TrimLeft(tok, `"'([“‘«`), `"')]’”»`)
+	for _, entity := range entities {
+		if tok == entity {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosingQuote determines if tok is a lone closing quotation mark.
+func isClosingQuote(tok string) bool {
+	return tok == `»` || tok == `”`
+}
+
+// isTitle determines if tok is one of the language's titles.
+func (a *multiPunctWordAnnotation) isTitle(tok string) bool {
+	typ, found := strings.CutSuffix(strings.ToLower(tok), ".")
+	return found && a.titles[typ]
+}
+
+// isKnownAbbr determines if tok is one of the model's abbreviations. Unlike
+// the type-based annotation, it doesn't mishandle non-ASCII tokens (e.g.,
+// Spanish's "pág.").
+func (a *multiPunctWordAnnotation) isKnownAbbr(tok string) bool {
+	typ, found := strings.CutSuffix(strings.ToLower(tok), ".")
+	if !found {
+		return false
+	}
+	parts := strings.Split(typ, "-")
+	return a.IsAbbr(typ, parts[len(parts)-1])
+}
+
 func (a *multiPunctWordAnnotation) tokenAnnotation(tokOne, tokTwo *sentences.Token) {
 	// This is an expensive calculation, so we only want to do it once.
 	var nextTyp string
@@ -208,6 +304,51 @@ func (a *multiPunctWordAnnotation) tokenAnnotation(tokOne, tokTwo *sentences.Tok
 		return
 	}
 
+	if isEntity(tokOne.Tok, a.entities) {
+		// An entity's punctuation (e.g., "Yahoo!") never ends a sentence.
+		tokOne.SentBreak = false
+		return
+	}
+
+	// A closing quotation mark that's separated from the punctuation before
+	// it (as is usual in French) ends the same sentence.
+	if tokOne.SentBreak && isClosingQuote(tokTwo.Tok) {
+		tokOne.SentBreak = false
+		tokTwo.SentBreak = true
+		return
+	}
+
+	if tokOne.SentBreak && tokTwo.Tok != "" && strings.ContainsAny(tokTwo.Tok[:1], ",;:)]}/%") {
+		// Sentences don't begin with these characters.
+		tokOne.SentBreak = false
+		return
+	}
+
+	if a.isTitle(tokOne.Tok) {
+		// Titles (e.g., "Dr.") precede names, so they never end a sentence.
+		tokOne.Abbr = true
+		tokOne.SentBreak = false
+		return
+	}
+
+	spaced := strings.TrimRight(tokOne.Tok+tokTwo.Tok, ".") + "."
+	if strings.HasSuffix(tokTwo.Tok, ".") && a.isKnownAbbr(spaced) {
+		// A spaced abbreviation, such as German's "z. B." or Spanish's
+		// "EE. UU.".
+		tokOne.Abbr = true
+		tokOne.SentBreak = false
+		return
+	}
+
+	if !tokOne.Abbr && a.isKnownAbbr(tokOne.Tok) {
+		// Reclassify the abbreviation as described in section 4.2 of the
+		// Punkt paper.
+		tokOne.Abbr = true
+		nextTyp = a.TokenParser.TypeNoSentPeriod(tokTwo)
+		tokOne.SentBreak = a.Ortho.Heuristic(tokTwo) == 1 ||
+			(a.TokenParser.FirstUpper(tokTwo) && a.SentStarters[nextTyp] != 0)
+	}
+
 	isNonBreak := strings.HasSuffix(tokOne.Tok, ".") && !tokOne.SentBreak
 	isEllipsis := reLooksLikeEllipsis.MatchString(tokOne.Tok)
 	isInternal := tokOne.SentBreak && looksInternal(tokOne.Tok)
@@ -244,7 +385,7 @@ func (a *multiPunctWordAnnotation) tokenAnnotation(tokOne, tokTwo *sentences.Tok
 		}
 	}
 
-	if len(reAbbr.FindAllString(tokOne.Tok, 1)) == 0 {
+	if !strings.HasSuffix(tokOne.Tok, ".") || len(reAbbr.FindAllString(tokOne.Tok, 1)) == 0 {
 		return
 	}
 
@@ -1,15 +1,18 @@
 package segment_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jdkato/twine/internal"
 	"github.com/jdkato/twine/nlp/segment"
 )
 
-var testdata = "../..testdata"
+var testdata = "../../testdata"
 var segmenter = segment.NewPunktSentenceTokenizer()
 
 type goldenRule struct {
@@ -37,6 +40,21 @@ func BenchmarkPunkt(b *testing.B) {
 	}
 }
 
+func BenchmarkPragmatic(b *testing.B) {
+	tests := make([]goldenRule, 0)
+	cases := internal.ReadDataFile(filepath.Join(testdata, "golden_rules_en.json"))
+	if err := json.Unmarshal(cases, &tests); err != nil {
+		panic(err)
+	}
+
+	s := segment.NewPragmaticSegmenter()
+	for n := 0; n < b.N; n++ {
+		for i := range tests {
+			_ = s.Segment(tests[i].Input)
+		}
+	}
+}
+
 func TestEnglishSmartQuotes(t *testing.T) {
 	actualText := "Here is a quote, ”a smart one.” Will this break properly?"
 	actual := segmenter.Segment(actualText)
@@ -488,3 +506,396 @@ func TestGoldenRules(t *testing.T) {
 	}
 	compareSentences(t, actualText, expected, test)*/
 }
+
+func checkGoldenRules(t *testing.T, s segment.Segmenter, language string) {
+	tests := make([]goldenRule, 0)
+	cases := internal.ReadDataFile(filepath.Join(testdata, "golden_rules_"+language+".json"))
+	if err := json.Unmarshal(cases, &tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		actual := s.Segment(test.Input)
+		if !reflect.DeepEqual(actual, test.Output) {
+			t.Errorf("%s: Actual: %q, Expected: %q", test.Name, actual, test.Output)
+		}
+	}
+}
+
+func checkPunktGoldenRules(t *testing.T, language string) {
+	s, err := segment.NewPunktSentenceTokenizerFor(language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGoldenRules(t, s, language)
+}
+
+func TestGoldenRulesSpanish(t *testing.T) {
+	checkPunktGoldenRules(t, "es")
+}
+
+func TestGoldenRulesFrench(t *testing.T) {
+	checkPunktGoldenRules(t, "fr")
+}
+
+func TestGoldenRulesPragmatic(t *testing.T) {
+	s := segment.NewPragmaticSegmenter()
+	checkGoldenRules(t, s, "en")
+
+	// The rules that golden_rules_en.json lacks.
+	cases := []goldenRule{
+		{"18. A.M. / P.M. as non sentence boundary and sentence boundary",
+			"At 5 a.m. Mr. Smith went to the bank. He left the bank at 6 P.M. Mr. Smith then went to the store.",
+			[]string{"At 5 a.m. Mr. Smith went to the bank.", "He left the bank at 6 P.M.", "Mr. Smith then went to the store."}},
+		{"31. List (period followed by parens and no period to end item)",
+			"1.) The first item 2.) The second item",
+			[]string{"1.) The first item", "2.) The second item"}},
+		{"32. List (period followed by parens and period to end item)",
+			"1.) The first item. 2.) The second item.",
+			[]string{"1.) The first item.", "2.) The second item."}},
+		{"33. List (parens and no period to end item)",
+			"1) The first item 2) The second item",
+			[]string{"1) The first item", "2) The second item"}},
+		{"34. List (parens and period to end item)",
+			"1) The first item. 2) The second item.",
+			[]string{"1) The first item.", "2) The second item."}},
+		{"35. List (period to mark list and no period to end item)",
+			"1. The first item 2. The second item",
+			[]string{"1. The first item", "2. The second item"}},
+		{"36. List (period to mark list and period to end item)",
+			"1. The first item. 2. The second item.",
+			[]string{"1. The first item.", "2. The second item."}},
+		{"37. List with bullet",
+			"• 9. The first item • 10. The second item",
+			[]string{"• 9. The first item", "• 10. The second item"}},
+		{"38. List with hypens",
+			"⁃9. The first item ⁃10. The second item",
+			[]string{"⁃9. The first item", "⁃10. The second item"}},
+		{"39. Alphabetical list",
+			"a. The first item b. The second item c. The third list item",
+			[]string{"a. The first item", "b. The second item", "c. The third list item"}},
+		{"52. No whitespace in between sentences",
+			"Hello world.Today is Tuesday.Mr. Smith went to the store and bought 1,000.That is a lot.",
+			[]string{"Hello world.", "Today is Tuesday.", "Mr. Smith went to the store and bought 1,000.", "That is a lot."}},
+		{"Paragraphs",
+			"The first paragraph\n\nThe second paragraph",
+			[]string{"The first paragraph", "The second paragraph"}},
+		{"Identifiers",
+			"Call os.Exit() to stop. Then call strings.ToLower on it.",
+			[]string{"Call os.Exit() to stop.", "Then call strings.ToLower on it."}},
+		{"Time followed by a sentence starter",
+			"Left at 5 p.m. He slept.",
+			[]string{"Left at 5 p.m.", "He slept."}},
+		{"Time introducing a sentence",
+			"By 10:30 a.m. Dr. Watson had arrived.",
+			[]string{"By 10:30 a.m. Dr. Watson had arrived."}},
+		{"Ellipsis character followed by a spaced period",
+			"Hi… . Bye.",
+			[]string{"Hi… .", "Bye."}},
+	}
+
+	for _, c := range cases {
+		if actual := s.Segment(c.Input); !reflect.DeepEqual(actual, c.Output) {
+			t.Errorf("%s: Actual: %q, Expected: %q", c.Name, actual, c.Output)
+		}
+	}
+
+	text := "Hi… . Bye."
+	expected := []segment.Sentence{
+		{Text: "Hi… .", Start: 0, End: 7, RuneStart: 0, RuneEnd: 5},
+		{Text: "Bye.", Start: 8, End: 12, RuneStart: 6, RuneEnd: 10},
+	}
+	if actual := s.Sentences(text); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestPunktLanguages(t *testing.T) {
+	cases := []struct {
+		language string
+		text     string
+		expected []string
+	}{
+		{"Spanish", "El Sr. García llegó. Vea la pág. 5, p. ej. aquí. Dijo que no. Luego se fue.", []string{
+			"El Sr. García llegó.", "Vea la pág. 5, p. ej. aquí.", "Dijo que no.", "Luego se fue."}},
+		{"fr", "M. Dupont est arrivé. Ensuite « Vraiment ? » dit-il. Oui.", []string{
+			"M. Dupont est arrivé.", "Ensuite « Vraiment ? » dit-il.", "Oui."}},
+		{"de", "Das ist z. B. ein Test. Herr Dr. Müller kam am 3. Oktober. Er wohnte dort usw. Dann ging er.", []string{
+			"Das ist z. B. ein Test.", "Herr Dr. Müller kam am 3. Oktober.", "Er wohnte dort usw.", "Dann ging er."}},
+		{"it", "Il sig. Rossi è arrivato. Vedi pag. 4 ecc. Poi andiamo.", []string{
+			"Il sig. Rossi è arrivato.", "Vedi pag. 4 ecc.", "Poi andiamo."}},
+		{"pt", "O Sr. Silva chegou. Veja a pág. 5, p.ex. aqui. Fim.", []string{
+			"O Sr. Silva chegou.", "Veja a pág. 5, p.ex. aqui.", "Fim."}},
+		{"Dutch", "Dhr. Jansen kwam bijv. gisteren. Mevr. De Vries ook.", []string{
+			"Dhr. Jansen kwam bijv. gisteren.", "Mevr. De Vries ook."}},
+		{"en", "Mr. Smith went to Washington. He arrived at 5 p.m. on Friday.", []string{
+			"Mr. Smith went to Washington.", "He arrived at 5 p.m. on Friday."}},
+	}
+
+	for _, c := range cases {
+		s, err := segment.NewPunktSentenceTokenizerFor(c.language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := s.Segment(c.text); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: Actual: %q, Expected: %q", c.language, actual, c.expected)
+		}
+	}
+
+	if _, err := segment.NewPunktSentenceTokenizerFor("klingon"); err == nil {
+		t.Error("Expected an error for an unsupported language")
+	}
+}
+
+func TestPunktTrainer(t *testing.T) {
+	trainer := segment.NewPunktTrainer()
+	trainer.Train(string(internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))))
+
+	model := trainer.Model()
+	for _, abbr := range []string{"mr", "mrs", "dr", "st"} {
+		if !contains(model.Abbreviations(), abbr) {
+			t.Errorf("Expected %q to be an abbreviation", abbr)
+		}
+	}
+	if len(model.SentenceStarters()) == 0 {
+		t.Error("Expected sentence starters")
+	}
+	if len(model.OrthographicContext()) == 0 {
+		t.Error("Expected orthographic context")
+	}
+
+	s := segment.NewPunktSentenceTokenizerFromModel(model)
+	text := "Mr. Holmes met Dr. Watson in London. They talked. Then they left."
+	expected := []string{"Mr. Holmes met Dr. Watson in London.", "They talked.", "Then they left."}
+	if actual := s.Segment(text); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %q, Expected: %q", actual, expected)
+	}
+}
+
+func TestPunktTrainerBaseModel(t *testing.T) {
+	base, err := segment.PunktModelFor("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trainer := segment.NewPunktTrainer(segment.UsingBaseModel(base))
+	trainer.Train(strings.Repeat(
+		"The first upload of a large repository usually takes two minutes. "+
+			"Edit the cfg. file in your home directory before you restart the "+
+			"background service. Each worker reads its cfg. settings when it "+
+			"starts and keeps them in memory.\n\n", 10))
+
+	model := trainer.Model()
+	for _, abbr := range []string{"cfg", "mr", "sgt"} {
+		if !contains(model.Abbreviations(), abbr) {
+			t.Errorf("Expected %q to be an abbreviation", abbr)
+		}
+	}
+	if contains(base.Abbreviations(), "cfg") {
+		t.Error("Expected the base model to be unchanged")
+	}
+
+	text := "Copy the cfg. file to the server. Then restart it."
+	expected := []string{"Copy the cfg. file to the server.", "Then restart it."}
+
+	s := segment.NewPunktSentenceTokenizerFromModel(model)
+	if actual := s.Segment(text); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %q, Expected: %q", actual, expected)
+	}
+	if actual := segmenter.Segment(text); reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected the English model to split after %q", "cfg.")
+	}
+}
+
+func TestPunktModelSaveLoad(t *testing.T) {
+	trainer := segment.NewPunktTrainer()
+	trainer.Train(string(internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))))
+	model := trainer.Model()
+	model.AddAbbreviations("Approx.")
+
+	var buf bytes.Buffer
+	if err := model.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := segment.LoadPunktModel(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.Abbreviations(), model.Abbreviations()) {
+		t.Error("Abbreviations differ after loading")
+	}
+	if !contains(loaded.Abbreviations(), "approx") {
+		t.Errorf("Expected %q to be an abbreviation", "approx")
+	}
+	if !reflect.DeepEqual(loaded.Collocations(), model.Collocations()) {
+		t.Error("Collocations differ after loading")
+	}
+	if !reflect.DeepEqual(loaded.SentenceStarters(), model.SentenceStarters()) {
+		t.Error("Sentence starters differ after loading")
+	}
+	if !reflect.DeepEqual(loaded.OrthographicContext(), model.OrthographicContext()) {
+		t.Error("Orthographic context differs after loading")
+	}
+
+	if _, err := segment.LoadPunktModel(strings.NewReader("{")); err == nil {
+		t.Error("Expected an error for an invalid model")
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSentences(t *testing.T) {
+	text := "  Él llegó a las 5 p.m. el día 3.\n\n¿Qué pasó después?  ¡Nada!  "
+	s, err := segment.NewPunktSentenceTokenizerFor("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []segment.Sentence{
+		{Text: "Él llegó a las 5 p.m. el día 3.", Start: 2, End: 36, RuneStart: 2, RuneEnd: 33},
+		{Text: "¿Qué pasó después?", Start: 38, End: 60, RuneStart: 35, RuneEnd: 53},
+		{Text: "¡Nada!", Start: 62, End: 69, RuneStart: 55, RuneEnd: 61},
+	}
+	if actual := s.Sentences(text); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Actual: %+v, Expected: %+v", actual, expected)
+	}
+
+	if actual := segmenter.Sentences(" \n "); len(actual) != 0 {
+		t.Errorf("Expected no sentences, got %+v", actual)
+	}
+}
+
+func TestSentencesOffsets(t *testing.T) {
+	tests := make([]goldenRule, 0)
+	cases := internal.ReadDataFile(filepath.Join(testdata, "golden_rules_en.json"))
+	if err := json.Unmarshal(cases, &tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []segment.Segmenter{segmenter, segment.NewPragmaticSegmenter()} {
+		for _, test := range tests {
+			runes := []rune(test.Input)
+			segmented := []string{}
+			for _, text := range s.Segment(test.Input) {
+				if text != "" {
+					segmented = append(segmented, text)
+				}
+			}
+
+			sents := s.Sentences(test.Input)
+			if len(sents) != len(segmented) {
+				t.Errorf("%s: %d sentences, expected %d", test.Name, len(sents), len(segmented))
+				continue
+			}
+			for i, sent := range sents {
+				if strings.Join(strings.Fields(sent.Text), " ") != strings.Join(strings.Fields(segmented[i]), " ") {
+					t.Errorf("%s: %q != %q", test.Name, sent.Text, segmented[i])
+				}
+				if test.Input[sent.Start:sent.End] != sent.Text {
+					t.Errorf("%s: bad byte offsets for %q", test.Name, sent.Text)
+				}
+				if string(runes[sent.RuneStart:sent.RuneEnd]) != sent.Text {
+					t.Errorf("%s: bad rune offsets for %q", test.Name, sent.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+	text := "Use the ver. 2 API. We use Go! for the backend. Ask Insp. Morse about it."
+	expected := []string{"Use the ver. 2 API.", "We use Go! for the backend.", "Ask Insp. Morse about it."}
+
+	vocab, err := segment.LoadVocabulary(filepath.Join(testdata, "vocabulary.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []segment.SegmenterOptFunc{
+		segment.UsingAbbreviations("Ver."),
+		segment.UsingNonBreakingPrefixes("insp"),
+		segment.UsingEntities("Go!"),
+	}
+	spanish, err := segment.NewPunktSentenceTokenizerFor("es", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, s := range map[string]segment.Segmenter{
+		"punkt":            segment.NewPunktSentenceTokenizer(opts...),
+		"punkt (es)":       spanish,
+		"punkt (model)":    segment.NewPunktSentenceTokenizerFromModel(mustModel(t, "en"), opts...),
+		"punkt (file)":     segment.NewPunktSentenceTokenizer(segment.UsingVocabulary(vocab)),
+		"pragmatic":        segment.NewPragmaticSegmenter(opts...),
+		"pragmatic (file)": segment.NewPragmaticSegmenter(segment.UsingVocabulary(vocab)),
+	} {
+		if actual := s.Segment(text); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: Actual: %q, Expected: %q", name, actual, expected)
+		}
+	}
+
+	if actual := segmenter.Segment(text); reflect.DeepEqual(actual, expected) {
+		t.Error("Expected the default vocabulary to split the text")
+	}
+}
+
+func TestVocabularyEntities(t *testing.T) {
+	opts := []segment.SegmenterOptFunc{segment.UsingEntities("Node.js", "Yum!")}
+
+	for _, c := range []struct {
+		text     string
+		expected []string
+	}{
+		{"Try Node.js. It works.", []string{"Try Node.js.", "It works."}},
+		{"Use Node.js for the backend. It works.",
+			[]string{"Use Node.js for the backend.", "It works."}},
+		{"We ate at (Yum!) last night. It was good.",
+			[]string{"We ate at (Yum!) last night.", "It was good."}},
+	} {
+		for name, s := range map[string]segment.Segmenter{
+			"punkt":     segment.NewPunktSentenceTokenizer(opts...),
+			"pragmatic": segment.NewPragmaticSegmenter(opts...),
+		} {
+			if actual := s.Segment(c.text); !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("%s: Actual: %q, Expected: %q", name, actual, c.expected)
+			}
+		}
+	}
+}
+
+func TestVocabularyErrors(t *testing.T) {
+	if _, err := segment.ParseVocabulary([]byte(`{"abbreviation": ["ver"]}`), false); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	if _, err := segment.ParseVocabulary([]byte("prefixes: ['']"), true); err == nil {
+		t.Error("Expected an error for an empty word")
+	}
+	if _, err := segment.LoadVocabulary(filepath.Join(testdata, "golden_rules_en.txt")); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	v, err := segment.ParseVocabulary([]byte(`{"entities": ["Yum!"]}`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v.Entities, []string{"Yum!"}) {
+		t.Errorf("Actual: %q", v.Entities)
+	}
+}
+
+func mustModel(t *testing.T, language string) *segment.PunktModel {
+	m, err := segment.PunktModelFor(language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -0,0 +1,379 @@
+package segment
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/neurosnap/sentences.v1"
+)
+
+// The thresholds and orthographic flags below are those of NLTK's
+// PunktTrainer, on which the training algorithm is based. See Kiss and
+// Strunk (2006), "Unsupervised Multilingual Sentence Boundary Detection."
+const (
+	// abbrevScore is the minimum score for a type to be an abbreviation.
+	abbrevScore = 0.3
+	// abbrevBackoff is the number of occurrences below which a type that's
+	// followed by lowercase words is considered a rare abbreviation.
+	abbrevBackoff = 5
+	// collocationScore is the minimum log-likelihood for a collocation.
+	collocationScore = 7.88
+	// sentStarterScore is the minimum log-likelihood for a sentence starter.
+	sentStarterScore = 30
+	// minCollocationFreq is the number of occurrences that a collocation
+	// must exceed.
+	minCollocationFreq = 1
+
+	orthoBegUc = 1 << 1
+	orthoMidUc = 1 << 2
+	orthoUnkUc = 1 << 3
+	orthoBegLc = 1 << 4
+	orthoMidLc = 1 << 5
+	orthoUnkLc = 1 << 6
+)
+
+var reNonPunct = regexp.MustCompile(`[^\W\d]`)
+
+// A PunktTrainer learns a PunktModel from raw text.
+//
+// Training is unsupervised: the text doesn't need to be segmented, but the
+// more of it there is, the better the model. Train may be called any number
+// of times before calling Model.
+type PunktTrainer struct {
+	word   *wordTokenizer
+	params *sentences.Storage
+
+	typeCounts      map[string]int
+	numTokens       int
+	numPeriodTokens int
+	numSentBreaks   int
+	starterCounts   map[string]int
+	collocCounts    map[[2]string]int
+}
+
+// PunktTrainerOptFunc configures a PunktTrainer.
+type PunktTrainerOptFunc func(*PunktTrainer)
+
+// UsingBaseModel starts training from an existing model (e.g., one from
+// PunktModelFor), which keeps its parameters and adds those that it learns.
+func UsingBaseModel(m *PunktModel) PunktTrainerOptFunc {
+	return func(t *PunktTrainer) {
+		t.params = m.copy()
+	}
+}
+
+// NewPunktTrainer creates a new PunktTrainer.
+func NewPunktTrainer(opts ...PunktTrainerOptFunc) *PunktTrainer {
+	t := &PunktTrainer{
+		word:          newWordTokenizer(sentences.NewPunctStrings()),
+		params:        sentences.NewStorage(),
+		typeCounts:    make(map[string]int),
+		starterCounts: make(map[string]int),
+		collocCounts:  make(map[[2]string]int),
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(t)
+	}
+
+	return t
+}
+
+// Train learns from text.
+func (t *PunktTrainer) Train(text string) {
+	tokens := t.word.Tokenize(text, false)
+
+	// Count each (case-normalized) type, without removing final periods.
+	types := make(map[string]bool)
+	for _, tok := range tokens {
+		typ := t.word.Type(tok)
+		t.typeCounts[typ]++
+		t.numTokens++
+		if t.word.HasPeriodFinal(tok) {
+			t.numPeriodTokens++
+		}
+		types[typ] = true
+	}
+
+	// Look for new abbreviations, and for types that no longer are.
+	for typ := range types {
+		t.reclassifyAbbrevType(typ)
+	}
+
+	// Mark likely sentence breaks, abbreviations and ellipses.
+	ellipses := t.annotateFirstPass(tokens)
+
+	t.learnOrthography(tokens, ellipses)
+	for _, tok := range tokens {
+		if tok.SentBreak {
+			t.numSentBreaks++
+		}
+	}
+
+	// The remaining heuristics apply to pairs of tokens in which the first
+	// ends with a period.
+	for i := 0; i+1 < len(tokens); i++ {
+		tok, next := tokens[i], tokens[i+1]
+		if !t.word.HasPeriodFinal(tok) {
+			continue
+		}
+
+		if t.isRareAbbrevType(tok, next) {
+			t.params.AbbrevTypes.Add(t.word.TypeNoPeriod(tok))
+		}
+		if t.isPotentialSentStarter(next, tok) {
+			t.starterCounts[t.word.Type(next)]++
+		}
+		if t.isPotentialCollocation(tok, next) {
+			t.collocCounts[[2]string{
+				t.word.TypeNoPeriod(tok), t.word.TypeNoSentPeriod(next)}]++
+		}
+	}
+}
+
+// Model returns the model learned from all of the text that the PunktTrainer
+// has seen so far.
+func (t *PunktTrainer) Model() *PunktModel {
+	m := &PunktModel{storage: t.params}
+	storage := m.copy()
+
+	for typ, atBreak := range t.starterCounts {
+		if typ == "" {
+			continue
+		}
+		count := t.typeCounts[typ] + t.typeCounts[typ+"."]
+		if count < atBreak {
+			continue
+		}
+		ll := colLogLikelihood(
+			float64(t.numSentBreaks), float64(count), float64(atBreak), float64(t.numTokens))
+		if ll >= sentStarterScore &&
+			float64(t.numTokens)/float64(t.numSentBreaks) > float64(count)/float64(atBreak) {
+			storage.SentStarters.Add(typ)
+		}
+	}
+
+	for pair, count := range t.collocCounts {
+		if storage.SentStarters.Has(pair[1]) {
+			continue
+		}
+		first := t.typeCounts[pair[0]] + t.typeCounts[pair[0]+"."]
+		second := t.typeCounts[pair[1]] + t.typeCounts[pair[1]+"."]
+		if first <= 1 || second <= 1 || count <= minCollocationFreq ||
+			count > first || count > second {
+			continue
+		}
+		ll := colLogLikelihood(
+			float64(first), float64(second), float64(count), float64(t.numTokens))
+		if ll >= collocationScore &&
+			float64(t.numTokens)/float64(first) > float64(second)/float64(count) {
+			storage.Collocations.Add(pair[0] + "," + pair[1])
+		}
+	}
+
+	return &PunktModel{storage: storage}
+}
+
+// reclassifyAbbrevType decides whether typ, which may end with a period, is
+// an abbreviation.
+func (t *PunktTrainer) reclassifyAbbrevType(typ string) {
+	if !reNonPunct.MatchString(typ) || typ == "##number##" {
+		return
+	}
+
+	isAdd := strings.HasSuffix(typ, ".")
+	if isAdd {
+		if t.params.AbbrevTypes.Has(typ) {
+			return
+		}
+		typ = typ[:len(typ)-1]
+	} else if !t.params.AbbrevTypes.Has(typ) {
+		return
+	}
+
+	// Find the log-likelihood that the type and a period occur together as
+	// a single unit, and scale it according to the number of periods, its
+	// length and how often it occurs without a period.
+	periods := strings.Count(typ, ".") + 1
+	nonPeriods := utf8.RuneCountInString(typ) - periods + 1
+	withPeriod := t.typeCounts[typ+"."]
+	withoutPeriod := t.typeCounts[typ]
+
+	ll := dunningLogLikelihood(
+		float64(withPeriod+withoutPeriod), float64(t.numPeriodTokens),
+		float64(withPeriod), float64(t.numTokens))
+	score := ll * math.Exp(-float64(nonPeriods)) * float64(periods) *
+		math.Pow(float64(nonPeriods), -float64(withoutPeriod))
+
+	if score >= abbrevScore && isAdd {
+		t.params.AbbrevTypes.Add(typ)
+	} else if score < abbrevScore && !isAdd {
+		t.params.AbbrevTypes.Remove(typ)
+	}
+}
+
+// annotateFirstPass marks the tokens that are likely sentence breaks or
+// abbreviations according to their types alone, returning which of them are
+// ellipses.
+func (t *PunktTrainer) annotateFirstPass(tokens []*sentences.Token) []bool {
+	ellipses := make([]bool, len(tokens))
+	for i, tok := range tokens {
+		switch {
+		case tok.Tok == "." || tok.Tok == "?" || tok.Tok == "!":
+			tok.SentBreak = true
+		case t.word.IsEllipsis(tok):
+			ellipses[i] = true
+		case t.word.HasPeriodFinal(tok) && !strings.HasSuffix(tok.Tok, ".."):
+			typ := strings.ToLower(strings.TrimSuffix(tok.Tok, "."))
+			parts := strings.Split(typ, "-")
+			if t.params.IsAbbr(typ, parts[len(parts)-1]) {
+				tok.Abbr = true
+			} else {
+				tok.SentBreak = true
+			}
+		}
+	}
+	return ellipses
+}
+
+// learnOrthography records the contexts in which each type occurs.
+func (t *PunktTrainer) learnOrthography(tokens []*sentences.Token, ellipses []bool) {
+	context := "internal"
+	for i, tok := range tokens {
+		// A paragraph break is a good sign of a sentence break, unless it
+		// follows an abbreviation.
+		if tok.ParaStart && context != "unknown" {
+			context = "initial"
+		}
+		// At the start of a line, it's impossible to tell whether a word is
+		// sentence-internal.
+		if tok.LineStart && context == "internal" {
+			context = "unknown"
+		}
+
+		typ := t.word.TypeNoSentPeriod(tok)
+		if flag := orthoFlag(context, t.word.FirstUpper(tok), t.word.FirstLower(tok)); flag != 0 {
+			t.params.OrthoContext[typ] |= flag
+		}
+
+		switch {
+		case tok.SentBreak && !t.isNumberOrInitial(tok):
+			context = "initial"
+		case tok.SentBreak, ellipses[i], tok.Abbr:
+			context = "unknown"
+		default:
+			context = "internal"
+		}
+	}
+}
+
+// isRareAbbrevType decides whether tok, which was marked as a sentence break,
+// is actually an uncommon abbreviation based on the token that follows it.
+func (t *PunktTrainer) isRareAbbrevType(tok, next *sentences.Token) bool {
+	if tok.Abbr || !tok.SentBreak {
+		return false
+	}
+
+	typ := t.word.TypeNoSentPeriod(tok)
+	_, size := utf8.DecodeLastRuneInString(typ)
+	count := t.typeCounts[typ] + t.typeCounts[typ[:len(typ)-size]]
+	if t.params.AbbrevTypes.Has(typ) || count >= abbrevBackoff {
+		return false
+	}
+
+	// It's an abbreviation if the next token is sentence-internal
+	// punctuation or a lowercase word that is sometimes capitalized, but
+	// never sentence-internally.
+	if strings.ContainsAny(next.Tok[:1], ",:;") {
+		return true
+	} else if t.word.FirstLower(next) {
+		ortho := t.params.OrthoContext[t.word.TypeNoSentPeriod(next)]
+		return ortho&orthoBegUc != 0 && ortho&orthoMidUc == 0
+	}
+
+	return false
+}
+
+// isPotentialSentStarter decides whether tok, which follows prev, is
+// alphabetic and follows a sentence break (that isn't an ordinal number or
+// an initial).
+func (t *PunktTrainer) isPotentialSentStarter(tok, prev *sentences.Token) bool {
+	return prev.SentBreak && !t.isNumberOrInitial(prev) && t.word.IsAlpha(tok)
+}
+
+// isPotentialCollocation decides whether tok and next, which are both words,
+// may be a collocation because tok is a number or initial followed by a
+// sentence break.
+func (t *PunktTrainer) isPotentialCollocation(tok, next *sentences.Token) bool {
+	return tok.SentBreak && t.isNumberOrInitial(tok) &&
+		reNonPunct.MatchString(t.word.Type(tok)) &&
+		reNonPunct.MatchString(t.word.Type(next))
+}
+
+func (t *PunktTrainer) isNumberOrInitial(tok *sentences.Token) bool {
+	return strings.HasPrefix(t.word.Type(tok), "##number##") || t.word.IsInitial(tok)
+}
+
+func orthoFlag(context string, upper, lower bool) int {
+	flags := map[string][2]int{
+		"initial":  {orthoBegUc, orthoBegLc},
+		"internal": {orthoMidUc, orthoMidLc},
+		"unknown":  {orthoUnkUc, orthoUnkLc},
+	}
+	switch {
+	case upper:
+		return flags[context][0]
+	case lower:
+		return flags[context][1]
+	}
+	return 0
+}
+
+// dunningLogLikelihood is the log-likelihood ratio that a and b (a period)
+// occur together as a unit, where the alternative hypothesis is that b
+// almost always follows a.
+func dunningLogLikelihood(countA, countB, countAB, n float64) float64 {
+	p1 := countB / n
+	p2 := 0.99
+
+	null := countAB*math.Log(p1+1e-8) + (countA-countAB)*math.Log(1-p1+1e-8)
+	alt := countAB*math.Log(p2) + (countA-countAB)*math.Log(1-p2)
+
+	return -2 * (null - alt)
+}
+
+// colLogLikelihood is Dunning's log-likelihood ratio that a and b are
+// dependent.
+func colLogLikelihood(countA, countB, countAB, n float64) float64 {
+	p := countB / n
+	p1 := countAB / countA
+	p2 := 1.0
+	if n != countA {
+		p2 = (countB - countAB) / (n - countA)
+	}
+
+	summand1 := logTerm(countAB, p) + logTerm(countA-countAB, 1-p)
+	summand2 := logTerm(countB-countAB, p) + logTerm(n-countA-countB+countAB, 1-p)
+
+	summand3 := 0.0
+	if countA != countAB && p1 > 0 && p1 < 1 {
+		summand3 = countAB*math.Log(p1) + (countA-countAB)*math.Log(1-p1)
+	}
+
+	summand4 := 0.0
+	if countB != countAB && p2 > 0 && p2 < 1 {
+		summand4 = (countB-countAB)*math.Log(p2) + (n-countA-countB+countAB)*math.Log(1-p2)
+	}
+
+	return -2 * (summand1 + summand2 - summand3 - summand4)
+}
+
+// logTerm returns count * log(p), treating 0 * log(0) as 0.
+func logTerm(count, p float64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return count * math.Log(p)
+}
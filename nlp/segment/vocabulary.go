@@ -0,0 +1,148 @@
+package segment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Vocabulary teaches a segmenter about words whose punctuation doesn't
+// (necessarily) end a sentence. It can be stored in a JSON or YAML file,
+// which allows each project to maintain its own.
+type Vocabulary struct {
+	// Abbreviations end a sentence only if the context suggests that they
+	// do (e.g., "approx."). They're case insensitive and don't need their
+	// final period.
+	Abbreviations []string `json:"abbreviations" yaml:"abbreviations"`
+
+	// Prefixes are abbreviations that precede names, so they never end a
+	// sentence (e.g., "Dr."). They're case insensitive and don't need their
+	// final period.
+	Prefixes []string `json:"prefixes" yaml:"prefixes"`
+
+	// Entities are names that end with punctuation that never ends a
+	// sentence (e.g., "Yahoo!"). They're case sensitive.
+	Entities []string `json:"entities" yaml:"entities"`
+}
+
+// defaultEntities are the entities that every segmenter knows.
+var defaultEntities = []string{"Yahoo!", "Jeopardy!"}
+
+// SegmenterOptFunc configures a Punkt or pragmatic segmenter by adding to
+// its Vocabulary.
+type SegmenterOptFunc func(*Vocabulary)
+
+// UsingAbbreviations adds abbreviations (e.g., "approx").
+func UsingAbbreviations(abbrevs ...string) SegmenterOptFunc {
+	return func(v *Vocabulary) {
+		v.Abbreviations = append(v.Abbreviations, abbrevs...)
+	}
+}
+
+// UsingNonBreakingPrefixes adds non-breaking prefixes (e.g., "Insp").
+func UsingNonBreakingPrefixes(prefixes ...string) SegmenterOptFunc {
+	return func(v *Vocabulary) {
+		v.Prefixes = append(v.Prefixes, prefixes...)
+	}
+}
+
+// UsingEntities adds protected entities (e.g., "Yum!").
+func UsingEntities(entities ...string) SegmenterOptFunc {
+	return func(v *Vocabulary) {
+		v.Entities = append(v.Entities, entities...)
+	}
+}
+
+// UsingVocabulary adds all of the words in vocab (e.g., one returned by
+// LoadVocabulary).
+func UsingVocabulary(vocab *Vocabulary) SegmenterOptFunc {
+	return func(v *Vocabulary) {
+		v.Abbreviations = append(v.Abbreviations, vocab.Abbreviations...)
+		v.Prefixes = append(v.Prefixes, vocab.Prefixes...)
+		v.Entities = append(v.Entities, vocab.Entities...)
+	}
+}
+
+// newVocabulary creates a segmenter's Vocabulary from its defaults and opts,
+// normalizing its abbreviations and prefixes.
+func newVocabulary(abbrevs, prefixes []string, opts []SegmenterOptFunc) *Vocabulary {
+	v := &Vocabulary{
+		Abbreviations: append([]string{}, abbrevs...),
+		Prefixes:      append([]string{}, prefixes...),
+		Entities:      append([]string{}, defaultEntities...),
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(v)
+	}
+
+	for _, list := range [][]string{v.Abbreviations, v.Prefixes} {
+		for i, word := range list {
+			list[i] = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(word)), ".")
+		}
+	}
+
+	return v
+}
+
+// ParseVocabulary parses a Vocabulary stored in JSON or, if isYAML is true,
+// YAML.
+//
+// Unknown fields are an error, so that a misspelled list isn't silently
+// ignored.
+func ParseVocabulary(data []byte, isYAML bool) (*Vocabulary, error) {
+	v := new(Vocabulary)
+
+	var err error
+	if isYAML {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(v)
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("segment: invalid vocabulary: %w", err)
+	}
+
+	for _, list := range [][]string{v.Abbreviations, v.Prefixes, v.Entities} {
+		for _, word := range list {
+			if strings.TrimSpace(word) == "" {
+				return nil, fmt.Errorf("segment: invalid vocabulary: empty word")
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// LoadVocabulary reads the Vocabulary stored at path, whose format is
+// determined by its extension (".json", ".yaml" or ".yml").
+func LoadVocabulary(path string) (*Vocabulary, error) {
+	var isYAML bool
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		isYAML = true
+	default:
+		return nil, fmt.Errorf("segment: %s: unknown vocabulary format", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := ParseVocabulary(data, isYAML)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,283 @@
+package stem
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// englishExceptions are words whose stems are given directly, rather than
+// being computed.
+var englishExceptions = map[string]string{
+	// Special changes.
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie",
+	"tying": "tie",
+	// Special -LY cases.
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli",
+	"only": "onli", "singly": "singl",
+	// Invariant forms.
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas",
+	"cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// englishInvariants are words that are left as they are once step 1a has
+// been applied.
+var englishInvariants = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+// englishPrefixes are prefixes that R1 starts after, regardless of the usual
+// definition; they'd otherwise give too short an R1 (e.g., "general" and
+// "generous" would conflate).
+var englishPrefixes = []string{"gener", "commun", "arsen"}
+
+var (
+	englishStep1a = newSuffixSet(
+		[]string{"sses"},
+		[]string{"ied", "ies"},
+		[]string{"s"},
+		[]string{"us", "ss"},
+	)
+	englishStep1b = newSuffixSet(
+		[]string{"eed", "eedly"},
+		[]string{"ed", "edly", "ing", "ingly"},
+	)
+	englishDoubles = newSuffixSet(
+		[]string{"bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt"},
+	)
+	englishStep2 = map[string]string{
+		"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able",
+		"entli": "ent", "izer": "ize", "ization": "ize", "ational": "ate",
+		"ation": "ate", "ator": "ate", "alism": "al", "aliti": "al",
+		"alli": "al", "fulness": "ful", "ousli": "ous", "ousness": "ous",
+		"iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+		"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+	}
+	englishStep3 = map[string]string{
+		"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic",
+		"iciti": "ic", "ical": "ic", "ful": "", "ness": "", "ative": "",
+	}
+	englishStep4 = newSuffixSet([]string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+		"ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+	})
+	englishStep2Suffixes = suffixesOf(englishStep2)
+	englishStep3Suffixes = suffixesOf(englishStep3)
+)
+
+// EnglishStemmer implements the English (Porter2) stemming algorithm.
+//
+// See https://snowballstem.org/algorithms/english/stemmer.html.
+type EnglishStemmer struct{}
+
+// NewEnglishStemmer is an EnglishStemmer constructor.
+func NewEnglishStemmer() *EnglishStemmer {
+	return &EnglishStemmer{}
+}
+
+// Stem returns the stem of word.
+func (s *EnglishStemmer) Stem(word string) string {
+	word = strings.ToLower(word)
+	if stem, found := englishExceptions[word]; found {
+		return stem
+	} else if utf8.RuneCountInString(word) < 3 {
+		return word
+	}
+
+	// Remove any initial apostrophe and mark the consonant Ys, which are
+	// restored once the suffixes have been removed.
+	word = strings.TrimPrefix(word, "'")
+	foundY := false
+	word = mapRunes(word, func(prev, r, _ rune) rune {
+		if r == 'y' && (prev == 0 || isEnglishVowel(prev)) {
+			foundY = true
+			return 'Y'
+		}
+		return r
+	})
+
+	r1 := -1
+	for _, prefix := range englishPrefixes {
+		if strings.HasPrefix(word, prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	if r1 < 0 {
+		r1 = afterNonVowel(word, 0, isEnglishVowel)
+	}
+	r2 := afterNonVowel(word, r1, isEnglishVowel)
+
+	word = englishStemStep1a(word)
+	if !englishInvariants[word] {
+		word = englishStemStep1b(word, r1)
+		word = englishStemStep1c(word)
+		word = englishStemStep2(word, r1)
+		word = englishStemStep3(word, r1, r2)
+		word = englishStemStep4(word, r2)
+		word = englishStemStep5(word, r1, r2)
+	}
+
+	if foundY {
+		word = strings.ReplaceAll(word, "Y", "y")
+	}
+	return word
+}
+
+// englishStemStep1a removes possessives and plurals.
+func englishStemStep1a(word string) string {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if strings.HasSuffix(word, suffix) {
+			word = replaceSuffix(word, suffix, "")
+			break
+		}
+	}
+
+	suffix, group := englishStep1a.find(word)
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 1:
+		return stem + "ss"
+	case 2:
+		if utf8.RuneCountInString(stem) > 1 {
+			return stem + "i"
+		}
+		return stem + "ie"
+	case 3:
+		// Delete the "s" if the preceding part contains a vowel that isn't
+		// immediately before it (e.g., "gas" and "this" are kept).
+		if stem != "" {
+			rest := stem[:len(stem)-utf8.RuneLen(lastRune(stem))]
+			if strings.IndexFunc(rest, isEnglishVowel) >= 0 {
+				return stem
+			}
+		}
+	}
+	return word
+}
+
+// englishStemStep1b removes "-ed", "-ing" and their "-ly" forms.
+func englishStemStep1b(word string, r1 int) string {
+	suffix, group := englishStep1b.find(word)
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 1:
+		if inRegion(word, suffix, r1) {
+			return stem + "ee"
+		}
+	case 2:
+		if strings.IndexFunc(stem, isEnglishVowel) < 0 {
+			break
+		}
+		if strings.HasSuffix(stem, "at") || strings.HasSuffix(stem, "bl") ||
+			strings.HasSuffix(stem, "iz") {
+			return stem + "e"
+		} else if double, _ := englishDoubles.find(stem); double != "" {
+			return stem[:len(stem)-1]
+		} else if r1 == len(stem) && isEnglishShortSyllable(stem) {
+			return stem + "e"
+		}
+		return stem
+	}
+	return word
+}
+
+// englishStemStep1c replaces a final "y" with "i" if it follows a
+// non-vowel that isn't the word's first letter (e.g., "cry" becomes "cri",
+// but "by" and "say" are kept).
+func englishStemStep1c(word string) string {
+	rs := []rune(word)
+	n := len(rs)
+	if n > 2 && (rs[n-1] == 'y' || rs[n-1] == 'Y') && !isEnglishVowel(rs[n-2]) {
+		return string(rs[:n-1]) + "i"
+	}
+	return word
+}
+
+// englishStemStep2 replaces derivational suffixes in R1.
+func englishStemStep2(word string, r1 int) string {
+	suffix, _ := englishStep2Suffixes.find(word)
+	if suffix == "" || !inRegion(word, suffix, r1) {
+		return word
+	}
+	stem := word[:len(word)-len(suffix)]
+	switch suffix {
+	case "ogi":
+		if !strings.HasSuffix(stem, "l") {
+			return word
+		}
+	case "li":
+		if !strings.ContainsRune("cdeghkmnrt", lastRune(stem)) || stem == "" {
+			return word
+		}
+	}
+	return stem + englishStep2[suffix]
+}
+
+// englishStemStep3 replaces derivational suffixes in R1.
+func englishStemStep3(word string, r1, r2 int) string {
+	suffix, _ := englishStep3Suffixes.find(word)
+	if suffix == "" || !inRegion(word, suffix, r1) {
+		return word
+	} else if suffix == "ative" && !inRegion(word, suffix, r2) {
+		return word
+	}
+	return replaceSuffix(word, suffix, englishStep3[suffix])
+}
+
+// englishStemStep4 removes derivational suffixes in R2.
+func englishStemStep4(word string, r2 int) string {
+	suffix, _ := englishStep4.find(word)
+	if suffix == "" || !inRegion(word, suffix, r2) {
+		return word
+	}
+	stem := word[:len(word)-len(suffix)]
+	if suffix == "ion" && !strings.HasSuffix(stem, "s") && !strings.HasSuffix(stem, "t") {
+		return word
+	}
+	return stem
+}
+
+// englishStemStep5 removes a final "e" or the second of a final "ll".
+func englishStemStep5(word string, r1, r2 int) string {
+	switch {
+	case strings.HasSuffix(word, "e"):
+		stem := word[:len(word)-1]
+		if inRegion(word, "e", r2) ||
+			(inRegion(word, "e", r1) && !isEnglishShortSyllable(stem)) {
+			return stem
+		}
+	case strings.HasSuffix(word, "ll"):
+		if inRegion(word, "l", r2) {
+			return word[:len(word)-1]
+		}
+	}
+	return word
+}
+
+// isEnglishShortSyllable reports whether word ends in a short syllable: a
+// vowel followed by a non-vowel other than "w", "x" or "Y" and preceded by
+// a non-vowel, or a vowel followed by a non-vowel at the beginning of the
+// word.
+func isEnglishShortSyllable(word string) bool {
+	rs := []rune(word)
+	n := len(rs)
+	if n >= 3 {
+		return !isEnglishVowel(rs[n-3]) && isEnglishVowel(rs[n-2]) &&
+			!isEnglishVowel(rs[n-1]) && !strings.ContainsRune("wxY", rs[n-1])
+	}
+	return n == 2 && isEnglishVowel(rs[0]) && !isEnglishVowel(rs[1])
+}
+
+func isEnglishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
+
+// suffixesOf returns a suffixSet of the keys of replacements.
+func suffixesOf(replacements map[string]string) *suffixSet {
+	suffixes := make([]string, 0, len(replacements))
+	for suffix := range replacements {
+		suffixes = append(suffixes, suffix)
+	}
+	return newSuffixSet(suffixes)
+}
@@ -0,0 +1,368 @@
+package stem
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	frenchPrefixes         = []string{"par", "col", "tap"}
+	frenchStandardSuffixes = newSuffixSet(
+		[]string{
+			"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+			"ismes", "ables", "istes",
+		},
+		[]string{"atrice", "ateur", "ation", "atrices", "ateurs", "ations"},
+		[]string{"logie", "logies"},
+		[]string{"usion", "ution", "usions", "utions"},
+		[]string{"ence", "ences"},
+		[]string{"ement", "ements"},
+		[]string{"ité", "ités"},
+		[]string{"if", "ive", "ifs", "ives"},
+		[]string{"eaux"},
+		[]string{"aux"},
+		[]string{"euse", "euses"},
+		[]string{"issement", "issements"},
+		[]string{"amment"},
+		[]string{"emment"},
+		[]string{"ment", "ments"},
+	)
+	frenchEmentSuffixes = newSuffixSet(
+		[]string{"iv"},
+		[]string{"eus"},
+		[]string{"abl", "iqU"},
+		[]string{"ièr", "Ièr"},
+	)
+	frenchIteSuffixes = newSuffixSet(
+		[]string{"abil"},
+		[]string{"ic"},
+		[]string{"iv"},
+	)
+	frenchIVerbSuffixes = newSuffixSet([]string{
+		"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai", "iraIent",
+		"irais", "irait", "iras", "irent", "irez", "iriez", "irions", "irons",
+		"iront", "is", "issaIent", "issais", "issait", "issant", "issante",
+		"issantes", "issants", "isse", "issent", "isses", "issez", "issiez",
+		"issions", "issons", "it",
+	})
+	frenchVerbSuffixes = newSuffixSet(
+		[]string{"ions"},
+		[]string{
+			"é", "ée", "ées", "és", "èrent", "er", "era", "erai", "eraIent",
+			"erais", "erait", "eras", "erez", "eriez", "erions", "erons",
+			"eront", "ez", "iez",
+		},
+		[]string{
+			"âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+			"ante", "antes", "ants", "as", "asse", "assent", "asses", "assiez",
+			"assions",
+		},
+	)
+	frenchResidualSuffixes = newSuffixSet(
+		[]string{"ion"},
+		[]string{"ier", "ière", "Ier", "Ière"},
+		[]string{"e"},
+		[]string{"ë"},
+	)
+	frenchDoubles = newSuffixSet([]string{"enn", "onn", "ett", "ell", "eill"})
+	frenchMarked  = strings.NewReplacer("I", "i", "U", "u", "Y", "y")
+)
+
+// FrenchStemmer implements the French Snowball stemming algorithm.
+//
+// See https://snowballstem.org/algorithms/french/stemmer.html.
+type FrenchStemmer struct{}
+
+// NewFrenchStemmer is a FrenchStemmer constructor.
+func NewFrenchStemmer() *FrenchStemmer {
+	return &FrenchStemmer{}
+}
+
+// Stem returns the stem of word.
+func (s *FrenchStemmer) Stem(word string) string {
+	word = frenchMarkConsonants(strings.ToLower(word))
+
+	rv := frenchRV(word)
+	r1 := afterNonVowel(word, 0, isFrenchVowel)
+	r2 := afterNonVowel(word, r1, isFrenchVowel)
+
+	stem, ok := frenchStemStandard(word, rv, r1, r2)
+	if !ok {
+		// The standard suffixes may have changed the word even if they
+		// weren't removed (e.g., "-amment" becomes "-ant").
+		word = stem
+		stem, ok = frenchStemIVerb(word, rv)
+	}
+	if !ok {
+		stem, ok = frenchStemVerb(word, rv, r2)
+	}
+
+	if ok {
+		word = stem
+		if strings.HasSuffix(word, "Y") {
+			word = replaceSuffix(word, "Y", "i")
+		} else if strings.HasSuffix(word, "ç") {
+			word = replaceSuffix(word, "ç", "c")
+		}
+	} else {
+		word = frenchStemResidual(stem, rv, r2)
+	}
+
+	if double, _ := frenchDoubles.find(word); double != "" {
+		word = word[:len(word)-1]
+	}
+	word = frenchUnaccent(word)
+
+	return frenchMarked.Replace(word)
+}
+
+// frenchMarkConsonants marks the "u" and "i" between vowels, the "y" next to
+// a vowel and the "u" after a "q" as consonants by uppercasing them.
+func frenchMarkConsonants(word string) string {
+	rs := []rune(word)
+	for i := range rs {
+		if isFrenchVowel(rs[i]) && i+1 < len(rs) {
+			switch next := rs[i+1]; {
+			case (next == 'u' || next == 'i') && i+2 < len(rs) && isFrenchVowel(rs[i+2]):
+				rs[i+1] = unicode.ToUpper(next)
+			case next == 'y':
+				rs[i+1] = 'Y'
+			}
+		}
+		if rs[i] == 'y' && i+1 < len(rs) && isFrenchVowel(rs[i+1]) {
+			rs[i] = 'Y'
+		} else if rs[i] == 'q' && i+1 < len(rs) && rs[i+1] == 'u' {
+			rs[i+1] = 'U'
+		}
+	}
+	return string(rs)
+}
+
+// frenchRV returns the offset of RV: if the word begins with two vowels, RV
+// is the region after the third letter; if it begins with "par", "col" or
+// "tap", it's the region after them; and otherwise it's the region after the
+// first vowel that isn't the first letter.
+func frenchRV(word string) int {
+	rs := []rune(word)
+	if len(rs) >= 3 && isFrenchVowel(rs[0]) && isFrenchVowel(rs[1]) {
+		return len(string(rs[:3]))
+	}
+	for _, prefix := range frenchPrefixes {
+		if strings.HasPrefix(word, prefix) {
+			return len(prefix)
+		}
+	}
+	if len(rs) > 1 {
+		start := utf8.RuneLen(rs[0])
+		if i := strings.IndexFunc(word[start:], isFrenchVowel); i >= 0 {
+			_, n := utf8.DecodeRuneInString(word[start+i:])
+			return start + i + n
+		}
+	}
+	return len(word)
+}
+
+// frenchStemStandard removes a derivational suffix, reporting whether one
+// was removed.
+func frenchStemStandard(word string, rv, r1, r2 int) (string, bool) {
+	suffix, group := frenchStandardSuffixes.find(word)
+	if suffix == "" {
+		return word, false
+	}
+	stem := word[:len(word)-len(suffix)]
+
+	switch group {
+	case 1:
+		if inRegion(word, suffix, r2) {
+			return stem, true
+		}
+	case 2:
+		if inRegion(word, suffix, r2) {
+			if strings.HasSuffix(stem, "ic") {
+				stem = frenchRemoveOr(stem, "ic", r2, "iqU")
+			}
+			return stem, true
+		}
+	case 3:
+		if inRegion(word, suffix, r2) {
+			return stem + "log", true
+		}
+	case 4:
+		if inRegion(word, suffix, r2) {
+			return stem + "u", true
+		}
+	case 5:
+		if inRegion(word, suffix, r2) {
+			return stem + "ent", true
+		}
+	case 6:
+		if !inRegion(word, suffix, rv) {
+			break
+		}
+		suffix, group := frenchEmentSuffixes.find(stem)
+		switch group {
+		case 1:
+			if inRegion(stem, suffix, r2) {
+				stem = removeInRegion(stem[:len(stem)-len(suffix)], "at", r2)
+			}
+		case 2:
+			if inRegion(stem, suffix, r2) {
+				stem = stem[:len(stem)-len(suffix)]
+			} else if inRegion(stem, suffix, r1) {
+				stem = replaceSuffix(stem, suffix, "eux")
+			}
+		case 3:
+			stem = removeInRegion(stem, suffix, r2)
+		case 4:
+			if inRegion(stem, suffix, rv) {
+				stem = replaceSuffix(stem, suffix, "i")
+			}
+		}
+		return stem, true
+	case 7:
+		if !inRegion(word, suffix, r2) {
+			break
+		}
+		suffix, group := frenchIteSuffixes.find(stem)
+		switch group {
+		case 1:
+			stem = frenchRemoveOr(stem, suffix, r2, "abl")
+		case 2:
+			stem = frenchRemoveOr(stem, suffix, r2, "iqU")
+		case 3:
+			stem = removeInRegion(stem, suffix, r2)
+		}
+		return stem, true
+	case 8:
+		if !inRegion(word, suffix, r2) {
+			break
+		}
+		if strings.HasSuffix(stem, "at") && inRegion(stem, "at", r2) {
+			stem = stem[:len(stem)-2]
+			if strings.HasSuffix(stem, "ic") {
+				stem = frenchRemoveOr(stem, "ic", r2, "iqU")
+			}
+		}
+		return stem, true
+	case 9:
+		return stem + "eau", true
+	case 10:
+		if inRegion(word, suffix, r1) {
+			return stem + "al", true
+		}
+	case 11:
+		if inRegion(word, suffix, r2) {
+			return stem, true
+		} else if inRegion(word, suffix, r1) {
+			return stem + "eux", true
+		}
+	case 12:
+		if inRegion(word, suffix, r1) && stem != "" && !isFrenchVowel(lastRune(stem)) {
+			return stem, true
+		}
+	case 13:
+		// The remaining suffixes are replaced (or removed) but don't count
+		// as having been removed, so the verb suffixes are still checked.
+		if inRegion(word, suffix, rv) {
+			return stem + "ant", false
+		}
+	case 14:
+		if inRegion(word, suffix, rv) {
+			return stem + "ent", false
+		}
+	case 15:
+		if r := lastRune(stem); isFrenchVowel(r) && inRegion(stem, string(r), rv) {
+			return stem, false
+		}
+	}
+	return word, false
+}
+
+// frenchStemIVerb removes a verb suffix beginning with "i" that follows a
+// non-vowel in RV, reporting whether one was removed.
+func frenchStemIVerb(word string, rv int) (string, bool) {
+	suffix, _ := frenchIVerbSuffixes.findIn(word, rv)
+	if suffix == "" {
+		return word, false
+	}
+	stem := word[:len(word)-len(suffix)]
+	if r := lastRune(stem); stem == "" || isFrenchVowel(r) || !inRegion(stem, string(r), rv) {
+		return word, false
+	}
+	return stem, true
+}
+
+// frenchStemVerb removes a verb suffix in RV, reporting whether one was
+// removed.
+func frenchStemVerb(word string, rv, r2 int) (string, bool) {
+	suffix, group := frenchVerbSuffixes.findIn(word, rv)
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 1:
+		if inRegion(word, suffix, r2) {
+			return stem, true
+		}
+	case 2:
+		return stem, true
+	case 3:
+		return removeInRegion(stem, "e", rv), true
+	}
+	return word, false
+}
+
+// frenchStemResidual removes a final "s" and then a residual suffix in RV.
+func frenchStemResidual(word string, rv, r2 int) string {
+	if stem, found := strings.CutSuffix(word, "s"); found && stem != "" &&
+		!strings.ContainsRune("aiouès", lastRune(stem)) {
+		word = stem
+	}
+
+	suffix, group := frenchResidualSuffixes.findIn(word, rv)
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 1:
+		if inRegion(word, suffix, r2) && inRegion(stem, "s", rv) &&
+			(strings.HasSuffix(stem, "s") || strings.HasSuffix(stem, "t")) {
+			return stem
+		}
+	case 2:
+		return stem + "i"
+	case 3:
+		return stem
+	case 4:
+		if strings.HasSuffix(stem, "gu") && inRegion(stem, "gu", rv) {
+			return stem
+		}
+	}
+	return word
+}
+
+// frenchUnaccent replaces an "é" or "è" that's followed by one or more
+// non-vowels at the end of word with "e".
+func frenchUnaccent(word string) string {
+	i := strings.LastIndexFunc(word, isFrenchVowel)
+	if i < 0 {
+		return word
+	}
+	switch r, n := utf8.DecodeRuneInString(word[i:]); r {
+	case 'é', 'è':
+		if i+n < len(word) {
+			return word[:i] + "e" + word[i+n:]
+		}
+	}
+	return word
+}
+
+// frenchRemoveOr removes suffix from word if it's in R2, replacing it with
+// repl otherwise.
+func frenchRemoveOr(word, suffix string, r2 int, repl string) string {
+	if inRegion(word, suffix, r2) {
+		return word[:len(word)-len(suffix)]
+	}
+	return replaceSuffix(word, suffix, repl)
+}
+
+func isFrenchVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyâàëéêèïîôûù", r)
+}
@@ -0,0 +1,142 @@
+package stem
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	germanStep1 = newSuffixSet(
+		[]string{"em", "ern", "er"},
+		[]string{"e", "en", "es"},
+		[]string{"s"},
+	)
+	germanStep2 = newSuffixSet(
+		[]string{"en", "er", "est"},
+		[]string{"st"},
+	)
+	germanStep3 = newSuffixSet(
+		[]string{"end", "ung"},
+		[]string{"ig", "ik", "isch"},
+		[]string{"lich", "heit"},
+		[]string{"keit"},
+	)
+	germanKeitSuffixes = newSuffixSet([]string{"lich", "ig"})
+	germanMarked       = strings.NewReplacer("U", "u", "Y", "y", "ä", "a", "ö", "o", "ü", "u")
+)
+
+// GermanStemmer implements the German Snowball stemming algorithm.
+//
+// See https://snowballstem.org/algorithms/german/stemmer.html.
+type GermanStemmer struct{}
+
+// NewGermanStemmer is a GermanStemmer constructor.
+func NewGermanStemmer() *GermanStemmer {
+	return &GermanStemmer{}
+}
+
+// Stem returns the stem of word.
+func (s *GermanStemmer) Stem(word string) string {
+	word = strings.ReplaceAll(strings.ToLower(word), "ß", "ss")
+
+	// Mark the "u" and "y" between vowels as consonants.
+	rs := []rune(word)
+	for i := 1; i < len(rs)-1; i++ {
+		if (rs[i] == 'u' || rs[i] == 'y') && isGermanVowel(rs[i-1]) && isGermanVowel(rs[i+1]) {
+			rs[i] = unicode.ToUpper(rs[i])
+		}
+	}
+	word = string(rs)
+
+	// R1 is adjusted so that the region before it contains at least three
+	// letters, but R2 is still defined relative to the unadjusted R1.
+	r1, r2 := len(word), len(word)
+	if len(rs) >= 3 {
+		r1 = afterNonVowel(word, 0, isGermanVowel)
+		r2 = afterNonVowel(word, r1, isGermanVowel)
+		if x := len(string(rs[:3])); r1 < x {
+			r1 = x
+		}
+	}
+
+	word = germanStemStep1(word, r1)
+	word = germanStemStep2(word, r1)
+	word = germanStemStep3(word, r1, r2)
+
+	return germanMarked.Replace(word)
+}
+
+// germanStemStep1 removes inflectional suffixes in R1.
+func germanStemStep1(word string, r1 int) string {
+	suffix, group := germanStep1.find(word)
+	if suffix == "" || !inRegion(word, suffix, r1) {
+		return word
+	}
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 2:
+		if strings.HasSuffix(stem, "niss") {
+			stem = stem[:len(stem)-1]
+		}
+	case 3:
+		if !isGermanSEnding(lastRune(stem)) {
+			return word
+		}
+	}
+	return stem
+}
+
+// germanStemStep2 removes inflectional suffixes in R1.
+func germanStemStep2(word string, r1 int) string {
+	suffix, group := germanStep2.find(word)
+	if suffix == "" || !inRegion(word, suffix, r1) {
+		return word
+	}
+	stem := word[:len(word)-len(suffix)]
+	if group == 2 {
+		r := lastRune(stem)
+		if !isGermanSEnding(r) || r == 'r' || utf8.RuneCountInString(stem) < 4 {
+			return word
+		}
+	}
+	return stem
+}
+
+// germanStemStep3 removes derivational suffixes in R2.
+func germanStemStep3(word string, r1, r2 int) string {
+	suffix, group := germanStep3.find(word)
+	if suffix == "" || !inRegion(word, suffix, r2) {
+		return word
+	}
+	stem := word[:len(word)-len(suffix)]
+	switch group {
+	case 1:
+		if strings.HasSuffix(stem, "ig") && !strings.HasSuffix(stem, "eig") {
+			stem = removeInRegion(stem, "ig", r2)
+		}
+	case 2:
+		if strings.HasSuffix(stem, "e") {
+			return word
+		}
+	case 3:
+		if strings.HasSuffix(stem, "er") {
+			stem = removeInRegion(stem, "er", r1)
+		} else if strings.HasSuffix(stem, "en") {
+			stem = removeInRegion(stem, "en", r1)
+		}
+	case 4:
+		if suffix, _ := germanKeitSuffixes.find(stem); suffix != "" {
+			stem = removeInRegion(stem, suffix, r2)
+		}
+	}
+	return stem
+}
+
+func isGermanVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyäöü", r)
+}
+
+func isGermanSEnding(r rune) bool {
+	return strings.ContainsRune("bdfghklmnrt", r)
+}
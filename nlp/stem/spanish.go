@@ -0,0 +1,236 @@
+package stem
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	spanishPronouns = newSuffixSet([]string{
+		"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo", "las",
+		"les", "los", "nos",
+	})
+	spanishPronounStems = newSuffixSet(
+		[]string{"iéndo", "ándo", "ár", "ér", "ír"},
+		[]string{"ando", "iendo", "ar", "er", "ir"},
+		[]string{"yendo"},
+	)
+	spanishStandardSuffixes = newSuffixSet(
+		[]string{
+			"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos",
+			"able", "ables", "ible", "ibles", "ista", "istas", "oso", "osa",
+			"osos", "osas", "amiento", "amientos", "imiento", "imientos",
+		},
+		[]string{
+			"adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+			"antes", "ancia", "ancias",
+		},
+		[]string{"logía", "logías"},
+		[]string{"ución", "uciones"},
+		[]string{"encia", "encias"},
+		[]string{"amente"},
+		[]string{"mente"},
+		[]string{"idad", "idades"},
+		[]string{"iva", "ivo", "ivas", "ivos"},
+	)
+	spanishAmenteSuffixes = newSuffixSet([]string{"iv"}, []string{"os", "ic", "ad"})
+	spanishMenteSuffixes  = newSuffixSet([]string{"ante", "able", "ible"})
+	spanishIdadSuffixes   = newSuffixSet([]string{"abil", "ic", "iv"})
+	spanishYVerbSuffixes  = newSuffixSet([]string{
+		"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes",
+		"yais", "yamos",
+	})
+	spanishVerbSuffixes = newSuffixSet(
+		[]string{"en", "es", "éis", "emos"},
+		[]string{
+			"arían", "arías", "arán", "arás", "aríais", "aría", "aréis",
+			"aríamos", "aremos", "ará", "aré", "erían", "erías", "erán",
+			"erás", "eríais", "ería", "eréis", "eríamos", "eremos", "erá",
+			"eré", "irían", "irías", "irán", "irás", "iríais", "iría", "iréis",
+			"iríamos", "iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara",
+			"iera", "ad", "ed", "id", "ase", "iese", "aste", "iste", "an",
+			"aban", "ían", "aran", "ieran", "asen", "iesen", "aron", "ieron",
+			"ado", "ido", "ando", "iendo", "ió", "ar", "er", "ir", "as", "abas",
+			"adas", "idas", "ías", "aras", "ieras", "ases", "ieses", "ís", "áis",
+			"abais", "íais", "arais", "ierais", "aseis", "ieseis", "asteis",
+			"isteis", "ados", "idos", "amos", "ábamos", "áramos", "iéramos",
+			"ásemos", "iésemos", "imos", "arémos", "erémos", "irémos",
+		},
+	)
+	spanishResidualSuffixes = newSuffixSet(
+		[]string{"os", "a", "o", "á", "í", "ó"},
+		[]string{"e", "é"},
+	)
+	spanishAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u")
+)
+
+// SpanishStemmer implements the Spanish Snowball stemming algorithm.
+//
+// See https://snowballstem.org/algorithms/spanish/stemmer.html.
+type SpanishStemmer struct{}
+
+// NewSpanishStemmer is a SpanishStemmer constructor.
+func NewSpanishStemmer() *SpanishStemmer {
+	return &SpanishStemmer{}
+}
+
+// Stem returns the stem of word.
+func (s *SpanishStemmer) Stem(word string) string {
+	word = strings.ToLower(word)
+
+	rv := regionRV(word, isSpanishVowel)
+	r1 := afterNonVowel(word, 0, isSpanishVowel)
+	r2 := afterNonVowel(word, r1, isSpanishVowel)
+
+	word = spanishStemPronoun(word, rv)
+	if stem, ok := spanishStemStandard(word, r1, r2); ok {
+		word = stem
+	} else if stem, ok := spanishStemYVerb(word, rv); ok {
+		word = stem
+	} else {
+		word = spanishStemVerb(word, rv)
+	}
+	word = spanishStemResidual(word, rv)
+
+	return spanishAccents.Replace(word)
+}
+
+// spanishStemPronoun removes an attached pronoun that follows a gerund or an
+// infinitive in RV (e.g., "dándole" becomes "dando").
+func spanishStemPronoun(word string, rv int) string {
+	pronoun, _ := spanishPronouns.find(word)
+	if pronoun == "" {
+		return word
+	}
+	stem := word[:len(word)-len(pronoun)]
+
+	suffix, group := spanishPronounStems.find(stem)
+	if suffix == "" || !inRegion(stem, suffix, rv) {
+		return word
+	}
+	switch group {
+	case 1:
+		return replaceSuffix(stem, suffix, spanishAccents.Replace(suffix))
+	case 3:
+		if !strings.HasSuffix(stem[:len(stem)-len(suffix)], "u") {
+			return word
+		}
+	}
+	return stem
+}
+
+// spanishStemStandard removes a derivational suffix, reporting whether one
+// was removed.
+func spanishStemStandard(word string, r1, r2 int) (string, bool) {
+	suffix, group := spanishStandardSuffixes.find(word)
+	if suffix == "" {
+		return word, false
+	} else if group == 6 {
+		if !inRegion(word, suffix, r1) {
+			return word, false
+		}
+	} else if !inRegion(word, suffix, r2) {
+		return word, false
+	}
+	word = word[:len(word)-len(suffix)]
+
+	switch group {
+	case 2:
+		word = removeInRegion(word, "ic", r2)
+	case 3:
+		word += "log"
+	case 4:
+		word += "u"
+	case 5:
+		word += "ente"
+	case 6:
+		if suffix, group := spanishAmenteSuffixes.find(word); suffix != "" && inRegion(word, suffix, r2) {
+			word = word[:len(word)-len(suffix)]
+			if group == 1 {
+				word = removeInRegion(word, "at", r2)
+			}
+		}
+	case 7:
+		if suffix, _ := spanishMenteSuffixes.find(word); suffix != "" {
+			word = removeInRegion(word, suffix, r2)
+		}
+	case 8:
+		if suffix, _ := spanishIdadSuffixes.find(word); suffix != "" {
+			word = removeInRegion(word, suffix, r2)
+		}
+	case 9:
+		word = removeInRegion(word, "at", r2)
+	}
+	return word, true
+}
+
+// spanishStemYVerb removes a verb suffix that begins with "y" and follows a
+// "u", reporting whether one was removed.
+func spanishStemYVerb(word string, rv int) (string, bool) {
+	suffix, _ := spanishYVerbSuffixes.findIn(word, rv)
+	if suffix == "" || !strings.HasSuffix(word[:len(word)-len(suffix)], "u") {
+		return word, false
+	}
+	return word[:len(word)-len(suffix)], true
+}
+
+// spanishStemVerb removes a verb suffix in RV.
+func spanishStemVerb(word string, rv int) string {
+	suffix, group := spanishVerbSuffixes.findIn(word, rv)
+	if suffix == "" {
+		return word
+	}
+	word = word[:len(word)-len(suffix)]
+	if group == 1 && strings.HasSuffix(word, "gu") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// spanishStemResidual removes a final vowel (or "os") in RV.
+func spanishStemResidual(word string, rv int) string {
+	suffix, group := spanishResidualSuffixes.find(word)
+	if suffix == "" || !inRegion(word, suffix, rv) {
+		return word
+	}
+	word = word[:len(word)-len(suffix)]
+	if group == 2 && strings.HasSuffix(word, "gu") {
+		word = removeInRegion(word, "u", rv)
+	}
+	return word
+}
+
+func isSpanishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouáéíóúü", r)
+}
+
+// regionRV returns the offset of RV, as defined for the Romance languages:
+// if the second letter is a consonant, RV is the region after the next
+// vowel; if the first two letters are vowels, it's the region after the next
+// consonant; and otherwise it's the region after the third letter.
+func regionRV(word string, isVowel func(rune) bool) int {
+	first, n1 := utf8.DecodeRuneInString(word)
+	second, n2 := utf8.DecodeRuneInString(word[n1:])
+	if n1 == 0 || n2 == 0 {
+		return len(word)
+	}
+	rest := n1 + n2
+
+	switch {
+	case !isVowel(second):
+		if i := strings.IndexFunc(word[rest:], isVowel); i >= 0 {
+			_, n := utf8.DecodeRuneInString(word[rest+i:])
+			return rest + i + n
+		}
+	case isVowel(first):
+		if i := strings.IndexFunc(word[rest:], func(r rune) bool { return !isVowel(r) }); i >= 0 {
+			_, n := utf8.DecodeRuneInString(word[rest+i:])
+			return rest + i + n
+		}
+	default:
+		if _, n := utf8.DecodeRuneInString(word[rest:]); n > 0 {
+			return rest + n
+		}
+	}
+	return len(word)
+}
@@ -0,0 +1,146 @@
+/*
+Package stem implements the Snowball stemming algorithms for English
+(Porter2), Spanish, French and German.
+
+See https://snowballstem.org/algorithms/ for a description of each algorithm.
+The implementations follow the reference Snowball sources. The English,
+French and Spanish stemmers are tested against the vocabulary and output lists
+distributed with them, and the German stemmer against the output of another
+implementation generated from them.
+*/
+package stem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Stemmer reduces inflected or derived words to their stem.
+type Stemmer interface {
+	// Stem returns the stem of a single word, which is lowercased first.
+	Stem(word string) string
+}
+
+// NewStemmer returns the Stemmer for the given language, which is identified
+// by its English name (e.g., "english") or its ISO 639-1 code (e.g., "en").
+func NewStemmer(language string) (Stemmer, error) {
+	switch strings.ToLower(language) {
+	case "english", "en":
+		return NewEnglishStemmer(), nil
+	case "spanish", "es":
+		return NewSpanishStemmer(), nil
+	case "french", "fr":
+		return NewFrenchStemmer(), nil
+	case "german", "de":
+		return NewGermanStemmer(), nil
+	}
+	return nil, fmt.Errorf("stem: unsupported language %q", language)
+}
+
+// A suffixSet is a collection of suffixes, each of which belongs to a group
+// that determines how the suffix is handled once it's found.
+type suffixSet struct {
+	suffixes []string // All suffixes, longest first.
+	groups   map[string]int
+}
+
+// newSuffixSet creates a suffixSet in which the suffixes of groups[i] belong
+// to group i+1.
+func newSuffixSet(groups ...[]string) *suffixSet {
+	set := &suffixSet{groups: make(map[string]int)}
+	for i, group := range groups {
+		for _, suffix := range group {
+			set.suffixes = append(set.suffixes, suffix)
+			set.groups[suffix] = i + 1
+		}
+	}
+	sort.SliceStable(set.suffixes, func(i, j int) bool {
+		return len(set.suffixes[i]) > len(set.suffixes[j])
+	})
+	return set
+}
+
+// find returns the longest suffix of word that's in the set, along with its
+// group. If there's no such suffix, find returns ("", 0).
+func (set *suffixSet) find(word string) (string, int) {
+	for _, suffix := range set.suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return suffix, set.groups[suffix]
+		}
+	}
+	return "", 0
+}
+
+// findIn returns the longest suffix of word that's in the set and starts at
+// or after offset start, along with its group.
+func (set *suffixSet) findIn(word string, start int) (string, int) {
+	for _, suffix := range set.suffixes {
+		if strings.HasSuffix(word, suffix) && inRegion(word, suffix, start) {
+			return suffix, set.groups[suffix]
+		}
+	}
+	return "", 0
+}
+
+// removeInRegion removes suffix from word if word ends with it and it starts
+// at or after offset r.
+func removeInRegion(word, suffix string, r int) string {
+	if strings.HasSuffix(word, suffix) && inRegion(word, suffix, r) {
+		return word[:len(word)-len(suffix)]
+	}
+	return word
+}
+
+// afterNonVowel returns the offset of the character after the first
+// non-vowel that follows a vowel in word[start:], or len(word) if there's no
+// such non-vowel. This is how R1 and R2 are defined by each of the
+// algorithms.
+func afterNonVowel(word string, start int, isVowel func(rune) bool) int {
+	prevVowel := false
+	for i, r := range word[start:] {
+		vowel := isVowel(r)
+		if prevVowel && !vowel {
+			return start + i + utf8.RuneLen(r)
+		}
+		prevVowel = vowel
+	}
+	return len(word)
+}
+
+// lastRune returns the last character of word, or utf8.RuneError if word is
+// empty.
+func lastRune(word string) rune {
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return r
+}
+
+// inRegion reports whether suffix, which word ends with, starts at or after
+// the region starting at offset r.
+func inRegion(word, suffix string, r int) bool {
+	return len(word)-len(suffix) >= r
+}
+
+// replaceSuffix replaces suffix, which word ends with, with repl.
+func replaceSuffix(word, suffix, repl string) string {
+	return word[:len(word)-len(suffix)] + repl
+}
+
+// mapRunes applies f to each character of word, along with the (already
+// mapped) character before it and the (original) character after it, which
+// are 0 at the word's boundaries.
+func mapRunes(word string, f func(prev, r, next rune) rune) string {
+	rs := []rune(word)
+	for i, r := range rs {
+		var prev, next rune
+		if i > 0 {
+			prev = rs[i-1]
+		}
+		if i < len(rs)-1 {
+			next = rs[i+1]
+		}
+		rs[i] = f(prev, r, next)
+	}
+	return string(rs)
+}
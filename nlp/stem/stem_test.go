@@ -0,0 +1,110 @@
+package stem_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jdkato/twine/nlp/stem"
+)
+
+var testdata = "../../testdata"
+
+// checkVocabulary stems each word of a vocabulary list, which holds a word
+// and its expected stem per line.
+//
+// The English, French and Spanish lists are the ones distributed with
+// Snowball (https://github.com/snowballstem/snowball-data). The German list
+// isn't: it pairs every other word of the Punkt German model's vocabulary
+// with its stem according to github.com/blevesearch/snowballstem/german,
+// which the Snowball compiler generated from the official german.sbl. It's
+// a cross-check of the algorithm rather than Snowball's own test data.
+func checkVocabulary(t *testing.T, language string) {
+	s, err := stem.NewStemmer(language)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(testdata, "stem_"+language+".txt.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failures, total := 0, 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		word, expected, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			continue
+		}
+		total++
+		if observed := s.Stem(word); observed != expected {
+			failures++
+			if failures <= 20 {
+				t.Errorf("%s: Expected '%s', got '%s'", word, expected, observed)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	} else if total == 0 {
+		t.Fatal("empty vocabulary")
+	} else if failures > 0 {
+		t.Errorf("%d/%d words were stemmed incorrectly", failures, total)
+	}
+}
+
+func TestEnglishVocabulary(t *testing.T) {
+	checkVocabulary(t, "english")
+}
+
+func TestSpanishVocabulary(t *testing.T) {
+	checkVocabulary(t, "spanish")
+}
+
+func TestFrenchVocabulary(t *testing.T) {
+	checkVocabulary(t, "french")
+}
+
+func TestGermanVocabulary(t *testing.T) {
+	checkVocabulary(t, "german")
+}
+
+func TestNewStemmer(t *testing.T) {
+	cases := []struct {
+		language string
+		word     string
+		expected string
+	}{
+		{"en", "Generously", "generous"},
+		{"English", "consignment", "consign"},
+		{"es", "Cantándole", "cant"},
+		{"fr", "Continuellement", "continuel"},
+		{"de", "Aufeinanderfolgenden", "aufeinanderfolg"},
+		{"german", "Straße", "strass"},
+	}
+
+	for _, c := range cases {
+		s, err := stem.NewStemmer(c.language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if observed := s.Stem(c.word); observed != c.expected {
+			t.Errorf("%s: Expected '%s', got '%s'", c.word, c.expected, observed)
+		}
+	}
+
+	if _, err := stem.NewStemmer("klingon"); err == nil {
+		t.Error("Expected an error for an unsupported language")
+	}
+}
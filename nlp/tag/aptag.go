@@ -21,7 +21,10 @@
 package tag
 
 import (
+	"math"
+	"math/rand"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -52,14 +55,18 @@ func NewAveragedPerceptron(weights map[string]map[string]float64,
 // PerceptronTagger is a port of Textblob's "fast and accurate" POS tagger.
 // See https://github.com/sloria/textblob-aptagger for details.
 type PerceptronTagger struct {
-	tagMap map[string]string
-	model  *AveragedPerceptron
+	model *AveragedPerceptron
 }
 
 // NewPerceptronTagger creates a new PerceptronTagger and loads the built-in
-// AveragedPerceptron model.
+// AveragedPerceptron model. It panics if the model can't be decoded; use
+// LoadPerceptronTagger to load a model of your own.
 func NewPerceptronTagger() *PerceptronTagger {
-	return &PerceptronTagger{model: NewAveragedPerceptron(wts, tags, classes)}
+	weights, tags, classes, err := loadBuiltin()
+	if err != nil {
+		panic(err)
+	}
+	return &PerceptronTagger{model: NewAveragedPerceptron(weights, tags, classes)}
 }
 
 //	 Wts returns the model's weights in the form
@@ -127,6 +134,93 @@ func (pt *PerceptronTagger) Tag(words []string) []Token {
 	return tokens
 }
 
+// TrainOptFunc configures the training of a PerceptronTagger.
+type TrainOptFunc func(*trainOptions)
+
+type trainOptions struct {
+	iterations int
+	seed       int64
+}
+
+// UsingIterations sets the number of passes over the training data (5 by
+// default).
+func UsingIterations(n int) TrainOptFunc {
+	return func(opts *trainOptions) {
+		opts.iterations = n
+	}
+}
+
+// UsingSeed sets the seed used to shuffle the training data between
+// iterations. Training is deterministic for a given seed (1 by default).
+func UsingSeed(seed int64) TrainOptFunc {
+	return func(opts *trainOptions) {
+		opts.seed = seed
+	}
+}
+
+// Train replaces the PerceptronTagger's model with one trained on sentences,
+// such as those returned by ReadTagged.
+//
+// Words that (nearly) always have the same tag in sentences are added to the
+// model's tag dictionary (see TagMap); the rest are used to train an averaged
+// perceptron over a number of iterations, between which the sentences are
+// shuffled. sentences itself isn't modified.
+func (pt *PerceptronTagger) Train(sentences TupleSlice, opts ...TrainOptFunc) {
+	options := trainOptions{iterations: 5, seed: 1}
+	for _, applyOpt := range opts {
+		applyOpt(&options)
+	}
+
+	pt.model = NewAveragedPerceptron(
+		make(map[string]map[string]float64), make(map[string]string), nil)
+	pt.makeTagMap(sentences)
+	sort.Strings(pt.model.classes)
+
+	shuffled := append(TupleSlice{}, sentences...)
+	rng := rand.New(rand.NewSource(options.seed))
+	for iter := 0; iter < options.iterations; iter++ {
+		for _, tuple := range shuffled {
+			pt.trainSentence(tuple[0], tuple[1])
+		}
+		rng.Shuffle(shuffled.Len(), shuffled.Swap)
+	}
+
+	pt.model.averageWeights()
+}
+
+// trainSentence updates the model's weights for a single sentence, tagging it
+// in the same way as Tag.
+func (pt *PerceptronTagger) trainSentence(words, truth []string) {
+	var clean, tags []string
+
+	p1, p2 := "-START-", "-START2-"
+	context := []string{p1, p2}
+	for i, w := range words {
+		if w == "" {
+			continue
+		}
+		context = append(context, normalize(w))
+		clean = append(clean, w)
+		tags = append(tags, truth[i])
+	}
+	context = append(context, []string{"-END-", "-END2-"}...)
+	for i, word := range clean {
+		var guess string
+		var found bool
+		if none.MatchString(word) {
+			guess = "-NONE-"
+		} else if keep.MatchString(word) {
+			guess = word
+		} else if guess, found = pt.model.tagMap[word]; !found {
+			feats := featurize(i, context, word, p1, p2)
+			guess = pt.model.predict(feats)
+			pt.model.update(tags[i], guess, feats)
+		}
+		p2 = p1
+		p1 = guess
+	}
+}
+
 func (pt *PerceptronTagger) makeTagMap(sentences TupleSlice) {
 	counts := make(map[string]map[string]int)
 	for _, tuple := range sentences {
@@ -144,7 +238,7 @@ func (pt *PerceptronTagger) makeTagMap(sentences TupleSlice) {
 		tag, mode := maxValue(tagFreqs)
 		n := float64(sumValues(tagFreqs))
 		if n >= 20 && (float64(mode)/n) >= 0.97 {
-			pt.tagMap[word] = tag
+			pt.model.tagMap[word] = tag
 		}
 	}
 }
@@ -182,28 +276,56 @@ func (ap *AveragedPerceptron) update(truth, guess string, feats map[string]float
 			ap.weights[f] = weights
 		}
 		ap.updateFeat(truth, f, get(truth, weights), 1.0)
-		ap.updateFeat(guess, f, get(guess, weights), -1.0)
+		if guess != "" {
+			ap.updateFeat(guess, f, get(guess, weights), -1.0)
+		}
 	}
 }
 
+// updateFeat adds w to the weight of feature f for class c, whose current
+// value is v, first accumulating v for each instance since its last update.
 func (ap *AveragedPerceptron) updateFeat(c, f string, v, w float64) {
 	key := f + "-" + c
-	ap.totals[key] = (ap.instances - ap.stamps[key]) * w
+	ap.totals[key] += (ap.instances - ap.stamps[key]) * v
 	ap.stamps[key] = ap.instances
 	ap.weights[f][c] = w + v
 }
 
+// averageWeights replaces each weight with its average over all of the
+// training instances, which generalizes much better than the final weights.
+func (ap *AveragedPerceptron) averageWeights() {
+	for feat, weights := range ap.weights {
+		averaged := make(map[string]float64)
+		for class, weight := range weights {
+			key := feat + "-" + class
+			total := ap.totals[key] + (ap.instances-ap.stamps[key])*weight
+			if avg := math.Round(total/ap.instances*1000) / 1000; avg != 0 {
+				averaged[class] = avg
+			}
+		}
+		if len(averaged) > 0 {
+			ap.weights[feat] = averaged
+		} else {
+			delete(ap.weights, feat)
+		}
+	}
+	ap.totals = make(map[string]float64)
+	ap.stamps = make(map[string]float64)
+}
+
 func (ap *AveragedPerceptron) addClass(class string) {
 	if !internal.StringInSlice(class, ap.classes) {
 		ap.classes = append(ap.classes, class)
 	}
 }
 
+// max returns the class with the highest score, breaking ties by choosing
+// the greatest label, or "" if there are no scores.
 func max(scores map[string]float64) string {
 	var class string
-	max := 0.0
+	max := math.Inf(-1)
 	for label, value := range scores {
-		if value > max {
+		if value > max || (value == max && label > class) {
 			max = value
 			class = label
 		}
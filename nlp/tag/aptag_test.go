@@ -1,7 +1,13 @@
 package tag
 
 import (
+	"bytes"
+	"encoding/gob"
 	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
 )
 
 var wsj = "Pierre|NNP Vinken|NNP ,|, 61|CD years|NNS old|JJ ,|, will|MD " +
@@ -23,3 +29,184 @@ func ExampleReadTagged() {
 	fmt.Println(ReadTagged(tagged, "|"))
 	// Output: [[[Pierre Vinken , 61 years] [NNP NNP , CD NNS]]]
 }
+
+func TestTrain(t *testing.T) {
+	sentences := ReadTagged(wsj, "|")
+	original := ReadTagged(wsj, "|")
+
+	pt := NewPerceptronTagger()
+	pt.Train(sentences, UsingIterations(10))
+
+	if !reflect.DeepEqual(sentences, original) {
+		t.Error("Train modified its input")
+	}
+	if len(pt.TagMap()) != 0 {
+		t.Errorf("Expected an empty tag map, got %v", pt.TagMap())
+	}
+	if !reflect.DeepEqual(pt.Classes(), []string{
+		",", ".", "CC", "CD", "DT", "IN", "JJ", "MD", "NN", "NNP", "NNS", "PRP",
+		"RB", "RBR", "TO", "VB", "VBD", "VBG", "VBN", "VBZ"}) {
+		t.Errorf("Unexpected classes: %v", pt.Classes())
+	}
+
+	correct, total := 0, 0
+	for _, tuple := range sentences {
+		for i, tok := range pt.Tag(tuple[0]) {
+			if tok.Tag == tuple[1][i] {
+				correct++
+			}
+			total++
+		}
+	}
+	if acc := float64(correct) / float64(total); acc < 0.95 {
+		t.Errorf("Expected an accuracy of at least 95%% on the training data, got %.2f%%", 100*acc)
+	}
+
+	tokens := pt.Tag(strings.Fields("Mr. Agnew is chairman of the board ."))
+	expected := []string{"NNP", "NNP", "VBZ", "NN", "IN", "DT", "NN", "."}
+	for i, tok := range tokens {
+		if tok.Tag != expected[i] {
+			t.Errorf("%s: got %s, expected %s", tok.Text, tok.Tag, expected[i])
+		}
+	}
+}
+
+func TestTrainTagMap(t *testing.T) {
+	sentences := ReadTagged(strings.Repeat(wsj+"\n", 9)+wsj, "|")
+
+	pt := NewPerceptronTagger()
+	pt.Train(sentences, UsingIterations(1))
+
+	for word, tag := range map[string]string{"the": "DT", "of": "IN", ".": ".", "a": "DT"} {
+		if pt.TagMap()[word] != tag {
+			t.Errorf("%s: got %q, expected %q", word, pt.TagMap()[word], tag)
+		}
+	}
+	if _, found := pt.TagMap()["Rudolph"]; found {
+		t.Error("Rudolph occurs fewer than 20 times")
+	}
+}
+
+func TestTrainSeed(t *testing.T) {
+	sentences := ReadTagged(wsj, "|")
+
+	a, b, c := NewPerceptronTagger(), NewPerceptronTagger(), NewPerceptronTagger()
+	a.Train(sentences, UsingSeed(42))
+	b.Train(sentences, UsingSeed(42))
+	c.Train(sentences, UsingSeed(7))
+
+	if !reflect.DeepEqual(a.Weights(), b.Weights()) {
+		t.Error("Expected the same weights for the same seed")
+	}
+	if reflect.DeepEqual(a.Weights(), c.Weights()) {
+		t.Error("Expected different weights for a different seed")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	pt := NewPerceptronTagger()
+	pt.Train(ReadTagged(wsj, "|"))
+
+	var buf bytes.Buffer
+	if err := pt.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPerceptronTagger(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.Weights(), pt.Weights()) {
+		t.Error("Weights differ after loading")
+	}
+	if !reflect.DeepEqual(loaded.TagMap(), pt.TagMap()) {
+		t.Error("TagMap differs after loading")
+	}
+	if !reflect.DeepEqual(loaded.Classes(), pt.Classes()) {
+		t.Error("Classes differ after loading")
+	}
+
+	words := strings.Fields("Mr. Agnew is chairman of the board .")
+	if !reflect.DeepEqual(loaded.Tag(words), pt.Tag(words)) {
+		t.Error("Tags differ after loading")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	encode := func(v interface{}) *bytes.Buffer {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+
+	for name, r := range map[string]*bytes.Buffer{
+		"empty":   {},
+		"garbage": bytes.NewBufferString("not a model"),
+		"other":   encode(map[string]int{"a": 1}),
+		"version": encode(savedModel{Version: modelVersion + 1}),
+		"missing": encode(struct{ Classes []string }{[]string{"NN"}}),
+	} {
+		if _, err := LoadPerceptronTagger(r); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	pt := &PerceptronTagger{model: NewAveragedPerceptron(
+		map[string]map[string]float64{"bias": {"NN": 1}},
+		map[string]string{"the": "DT", "dog": "NN", "runs": "NN"},
+		[]string{"DT", "NN", "VBZ"})}
+
+	e := pt.Evaluate(ReadTagged("the|DT dog|NN runs|VBZ\nthe|DT cat|NN", "|"))
+	if e.Tokens != 5 || e.Correct != 4 || e.Unknown != 1 || e.UnknownCorrect != 1 {
+		t.Errorf("Unexpected counts: %+v", e)
+	}
+	if e.Accuracy() != 0.8 || e.UnknownAccuracy() != 1 {
+		t.Errorf("Unexpected accuracies: %v, %v", e.Accuracy(), e.UnknownAccuracy())
+	}
+	if e.Confusion["VBZ"]["NN"] != 1 || e.Confusion["NN"]["NN"] != 2 {
+		t.Errorf("Unexpected confusion matrix: %v", e.Confusion)
+	}
+
+	expected := []TagScore{
+		{Tag: "DT", Precision: 1, Recall: 1, F1: 1, Support: 2},
+		{Tag: "NN", Precision: 2.0 / 3, Recall: 1, F1: 0.8, Support: 2},
+		{Tag: "VBZ", Support: 1},
+	}
+	scores := e.Scores()
+	if len(scores) != len(expected) {
+		t.Fatalf("Unexpected scores: %+v", scores)
+	}
+	for i, s := range scores {
+		if s.Tag != expected[i].Tag || s.Support != expected[i].Support ||
+			math.Abs(s.Precision-expected[i].Precision) > 1e-9 ||
+			math.Abs(s.Recall-expected[i].Recall) > 1e-9 ||
+			math.Abs(s.F1-expected[i].F1) > 1e-9 {
+			t.Errorf("Actual: %+v, Expected: %+v", s, expected[i])
+		}
+	}
+
+	report := e.String()
+	for _, line := range []string{
+		"Accuracy:              80.00% (4/5)",
+		"NN     0.6667  1.0000  0.8000        2",
+		"VBZ  .  1   .",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("Expected %q in the report:\n%s", line, report)
+		}
+	}
+}
+
+func ExampleEvaluation() {
+	pt := NewPerceptronTagger()
+	pt.Train(ReadTagged(wsj, "|"))
+
+	e := pt.Evaluate(ReadTagged("The|DT board|NN named|VBN a|DT director|NN .|.", "|"))
+	fmt.Printf("%.2f %d\n", e.Accuracy(), e.Unknown)
+	// Output: 1.00 0
+}
@@ -4,12 +4,12 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
 )
 
-var wts map[string]map[string]float64
-var tags map[string]string
-var classes []string
-
 //go:embed classes.gob
 var encodedClasses []byte
 
@@ -19,22 +19,83 @@ var encodedTags []byte
 //go:embed weights.gob
 var encodedWeights []byte
 
-func init() {
-	dec := gob.NewDecoder(bytes.NewReader(encodedClasses))
-	err := dec.Decode(&classes)
-	if err != nil {
-		panic(err)
+// modelVersion is the version of the format written by PerceptronTagger.Save.
+// It must be incremented whenever savedModel changes incompatibly.
+const modelVersion = 1
+
+// savedModel is the on-disk format of a PerceptronTagger.
+type savedModel struct {
+	Version int
+	Weights map[string]map[string]float64
+	TagMap  map[string]string
+	Classes []string
+}
+
+var builtin struct {
+	once    sync.Once
+	weights map[string]map[string]float64
+	tags    map[string]string
+	classes []string
+	err     error
+}
+
+// loadBuiltin decodes the embedded model the first time that it's called.
+func loadBuiltin() (map[string]map[string]float64, map[string]string, []string, error) {
+	builtin.once.Do(func() {
+		for _, blob := range []struct {
+			data []byte
+			dest interface{}
+		}{
+			{encodedClasses, &builtin.classes},
+			{encodedTags, &builtin.tags},
+			{encodedWeights, &builtin.weights},
+		} {
+			if err := gob.NewDecoder(bytes.NewReader(blob.data)).Decode(blob.dest); err != nil {
+				builtin.err = fmt.Errorf("tag: invalid built-in model: %w", err)
+				return
+			}
+		}
+	})
+	return builtin.weights, builtin.tags, builtin.classes, builtin.err
+}
+
+// Save writes the PerceptronTagger's model -- its weights, tag map and
+// classes -- to w, in a versioned format that LoadPerceptronTagger reads.
+func (pt *PerceptronTagger) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(savedModel{
+		Version: modelVersion,
+		Weights: pt.model.weights,
+		TagMap:  pt.model.tagMap,
+		Classes: pt.model.classes,
+	})
+}
+
+// LoadPerceptronTagger creates a new PerceptronTagger from a model that was
+// written by PerceptronTagger.Save.
+func LoadPerceptronTagger(r io.Reader) (*PerceptronTagger, error) {
+	var m savedModel
+	if err := gob.NewDecoder(r).Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("tag: invalid model: %w", err)
 	}
 
-	dec = gob.NewDecoder(bytes.NewReader(encodedTags))
-	err = dec.Decode(&tags)
-	if err != nil {
-		panic(err)
+	switch {
+	case m.Version == 0:
+		return nil, errors.New("tag: invalid model: missing version")
+	case m.Version > modelVersion:
+		return nil, fmt.Errorf("tag: unsupported model version %d (expected at most %d)",
+			m.Version, modelVersion)
 	}
 
-	dec = gob.NewDecoder(bytes.NewReader(encodedWeights))
-	err = dec.Decode(&wts)
-	if err != nil {
-		panic(err)
+	if m.Weights == nil {
+		m.Weights = make(map[string]map[string]float64)
+	}
+	if m.TagMap == nil {
+		m.TagMap = make(map[string]string)
 	}
+
+	return &PerceptronTagger{
+		model: NewAveragedPerceptron(m.Weights, m.TagMap, m.Classes)}, nil
 }
@@ -0,0 +1,203 @@
+package tag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// An Evaluation records how well a PerceptronTagger tagged a set of
+// gold-standard sentences.
+type Evaluation struct {
+	Tokens         int // The number of tokens.
+	Correct        int // The number of correctly-tagged tokens.
+	Unknown        int // The number of tokens whose word the model hasn't seen.
+	UnknownCorrect int // The number of correctly-tagged unknown tokens.
+
+	// Confusion counts how often each gold tag (the outer key) was tagged as
+	// each predicted tag (the inner key).
+	Confusion map[string]map[string]int
+}
+
+// A TagScore holds the precision, recall and F1 score of a single tag.
+type TagScore struct {
+	Tag       string
+	Precision float64
+	Recall    float64
+	F1        float64
+	Support   int // The number of tokens with this gold tag.
+}
+
+// Evaluate tags each of the gold sentences (e.g., those returned by
+// ReadTagged) and compares the result to their tags.
+//
+// A word is unknown if it's neither in the model's tag dictionary nor one of
+// the words that it has weights for, which approximates whether it occurred
+// in the model's training data.
+func (pt *PerceptronTagger) Evaluate(gold TupleSlice) *Evaluation {
+	e := &Evaluation{Confusion: make(map[string]map[string]int)}
+	for _, tuple := range gold {
+		var words, tags []string
+		for i, word := range tuple[0] {
+			if word != "" {
+				words = append(words, word)
+				tags = append(tags, tuple[1][i])
+			}
+		}
+
+		for i, tok := range pt.Tag(words) {
+			if e.Confusion[tags[i]] == nil {
+				e.Confusion[tags[i]] = make(map[string]int)
+			}
+			e.Confusion[tags[i]][tok.Tag]++
+
+			correct := tok.Tag == tags[i]
+			e.Tokens++
+			if correct {
+				e.Correct++
+			}
+			if !pt.knows(tok.Text) {
+				e.Unknown++
+				if correct {
+					e.UnknownCorrect++
+				}
+			}
+		}
+	}
+	return e
+}
+
+// knows reports whether word is in the model's tag dictionary or has weights.
+func (pt *PerceptronTagger) knows(word string) bool {
+	if _, found := pt.model.tagMap[word]; found {
+		return true
+	}
+	_, found := pt.model.weights["i word "+normalize(word)]
+	return found
+}
+
+// Accuracy returns the fraction of tokens that were tagged correctly.
+func (e *Evaluation) Accuracy() float64 {
+	return ratio(e.Correct, e.Tokens)
+}
+
+// UnknownAccuracy returns the fraction of unknown tokens that were tagged
+// correctly.
+func (e *Evaluation) UnknownAccuracy() float64 {
+	return ratio(e.UnknownCorrect, e.Unknown)
+}
+
+// Tags returns every tag that occurs in the gold data or the predictions, in
+// sorted order.
+func (e *Evaluation) Tags() []string {
+	seen := make(map[string]bool)
+	for gold, predictions := range e.Confusion {
+		seen[gold] = true
+		for predicted := range predictions {
+			seen[predicted] = true
+		}
+	}
+
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Scores returns the precision, recall and F1 score of each of the tags
+// returned by Tags.
+func (e *Evaluation) Scores() []TagScore {
+	predicted := make(map[string]int)
+	for _, predictions := range e.Confusion {
+		for tag, n := range predictions {
+			predicted[tag] += n
+		}
+	}
+
+	scores := []TagScore{}
+	for _, tag := range e.Tags() {
+		support := 0
+		for _, n := range e.Confusion[tag] {
+			support += n
+		}
+
+		correct := e.Confusion[tag][tag]
+		s := TagScore{
+			Tag:       tag,
+			Precision: ratio(correct, predicted[tag]),
+			Recall:    ratio(correct, support),
+			Support:   support,
+		}
+		if s.Precision+s.Recall > 0 {
+			s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+		}
+		scores = append(scores, s)
+	}
+	return scores
+}
+
+// String returns a report of the Evaluation: its accuracies, each tag's
+// scores and the confusion matrix, whose rows are gold tags and whose columns
+// are predicted tags.
+func (e *Evaluation) String() string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Accuracy:\t%.2f%% (%d/%d)\n", 100*e.Accuracy(), e.Correct, e.Tokens)
+	fmt.Fprintf(w, "Unknown-word accuracy:\t%.2f%% (%d/%d)\n",
+		100*e.UnknownAccuracy(), e.UnknownCorrect, e.Unknown)
+	w.Flush()
+
+	sb.WriteString("\n")
+
+	w = tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Tag\tPrecision\tRecall\tF1\tSupport\t")
+	for _, s := range e.Scores() {
+		fmt.Fprintf(w, "%s\t%.4f\t%.4f\t%.4f\t%d\t\n",
+			displayTag(s.Tag), s.Precision, s.Recall, s.F1, s.Support)
+	}
+	w.Flush()
+
+	sb.WriteString("\n")
+
+	tags := e.Tags()
+	w = tabwriter.NewWriter(&sb, 0, 0, 1, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "\t")
+	for _, tag := range tags {
+		fmt.Fprintf(w, "%s\t", displayTag(tag))
+	}
+	fmt.Fprintln(w)
+	for _, gold := range tags {
+		fmt.Fprintf(w, "%s\t", displayTag(gold))
+		for _, predicted := range tags {
+			if n := e.Confusion[gold][predicted]; n > 0 {
+				fmt.Fprintf(w, "%d\t", n)
+			} else {
+				fmt.Fprint(w, ".\t")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+// displayTag returns the name of tag in a report, in which the empty tag
+// (i.e., no prediction) is shown as "-".
+func displayTag(tag string) string {
+	if tag == "" {
+		return "-"
+	}
+	return tag
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
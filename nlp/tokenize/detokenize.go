@@ -0,0 +1,117 @@
+package tokenize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TreebankWordDetokenizer joins words produced by TreebankWordTokenizer back
+// into a sentence.
+//
+// This implementation is a port of NLTK's TreebankWordDetokenizer: it undoes
+// the tokenizer's rules in reverse order, re-attaching contractions and
+// clitics, removing the padding around punctuation and brackets, and
+// converting Treebank-style quotes back into double quotes.
+//
+// Detokenization is lossy: the tokenizer discards the original whitespace,
+// so line breaks and padded punctuation (e.g., "the end ." or "wait ... what")
+// are not restored. The words themselves are, however, preserved:
+// re-tokenizing the output of Detokenize yields the original words.
+type TreebankWordDetokenizer struct {
+}
+
+// NewTreebankWordDetokenizer is a TreebankWordDetokenizer constructor.
+func NewTreebankWordDetokenizer() *TreebankWordDetokenizer {
+	return new(TreebankWordDetokenizer)
+}
+
+// A detokenizerRule replaces every match of pattern with replacement.
+type detokenizerRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func (r detokenizerRule) apply(text string) string {
+	return r.pattern.ReplaceAllString(text, r.replacement)
+}
+
+func newDetokenizerRules(rules [][2]string) []detokenizerRule {
+	compiled := make([]detokenizerRule, len(rules))
+	for i, rule := range rules {
+		compiled[i] = detokenizerRule{regexp.MustCompile(rule[0]), rule[1]}
+	}
+	return compiled
+}
+
+// detokenizerContractions reverse the tokenizer's contraction rules.
+var detokenizerContractions = newDetokenizerRules([][2]string{
+	{`(?i) ('t)\s(is)\b`, ` ${1}${2}`},
+	{`(?i) ('t)\s(was)\b`, ` ${1}${2}`},
+	{`(?i)\b(can)\s(not)\b`, `${1}${2}`},
+	{`(?i)\b(d)\s('ye)\b`, `${1}${2}`},
+	{`(?i)\b(gim)\s(me)\b`, `${1}${2}`},
+	{`(?i)\b(gon)\s(na)\b`, `${1}${2}`},
+	{`(?i)\b(got)\s(ta)\b`, `${1}${2}`},
+	{`(?i)\b(lem)\s(me)\b`, `${1}${2}`},
+	{`(?i)\b(mor)\s('n)\b`, `${1}${2}`},
+	{`(?i)\b(wan)\s(na)(\s)`, `${1}${2}${3}`},
+})
+
+// detokenizerEndingQuotes re-attach clitics and closing quotes.
+var detokenizerEndingQuotes = newDetokenizerRules([][2]string{
+	{`([^' ])\s('ll|'LL|'re|'RE|'ve|'VE|n't|N'T) `, `${1}${2} `},
+	{`([^' ])\s('[sS]|'[mM]|'[dD]|') `, `${1}${2} `},
+	{`(\S)\s('')`, `${1}${2}`},
+	{`('')\s([.,:)\]>};%])`, `${1}${2}`},
+	{`''`, `"`},
+})
+
+// detokenizerPunctuation remove the padding around dashes, brackets and
+// punctuation.
+var detokenizerPunctuation = newDetokenizerRules([][2]string{
+	{` -- `, `--`},
+
+	{`([\[\(\{\<])\s`, `${1}`},
+	{`\s([\]\)\}\>])`, `${1}`},
+	{`([\]\)\}\>])\s([:;,.])`, `${1}${2}`},
+
+	{`([^'])\s'\s`, `${1}' `},
+	{`\s([?!])`, `${1}`},
+	{`([^\.])\s(\.)([\]\)}>"\']*)\s*$`, `${1}${2}${3}`},
+	{`([#$])\s`, `${1}`},
+	{`\s([;%])`, `${1}`},
+	{`\s\.\.\.\s`, `...`},
+	{`\s([:,])`, `${1}`},
+})
+
+// detokenizerStartingQuotes convert opening quotes back into double quotes.
+var detokenizerStartingQuotes = newDetokenizerRules([][2]string{
+	{"([ (\\[{<])\\s``", "${1}``"},
+	{"(``)\\s", "${1}"},
+	{"``", `"`},
+})
+
+// Detokenize joins words into a sentence.
+//
+// The words are expected to follow the Treebank conventions (e.g., contractions
+// split as [do n't]), as produced by TreebankWordTokenizer.
+func (t TreebankWordDetokenizer) Detokenize(words []string) string {
+	text := " " + strings.Join(words, " ") + " "
+
+	for _, rule := range detokenizerContractions {
+		text = rule.apply(text)
+	}
+	for _, rule := range detokenizerEndingQuotes {
+		text = rule.apply(text)
+	}
+
+	text = strings.TrimSpace(text)
+	for _, rule := range detokenizerPunctuation {
+		text = rule.apply(text)
+	}
+	for _, rule := range detokenizerStartingQuotes {
+		text = rule.apply(text)
+	}
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,377 @@
+/*
+Package dictionary implements a tokenizer that uses dictionaries to split text
+written without spaces between its words (e.g., Chinese, Japanese and Thai).
+
+It's separate from the tokenize package because of the size of its built-in
+dictionaries, which were extracted from the word-break dictionaries
+distributed with ICU 74 (cjdict and thaidict) and are available under the
+Unicode License; see the NOTICE file at the root of this repository.
+*/
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jdkato/twine/nlp/tokenize"
+)
+
+//go:embed cjdict.gz
+var encodedCJDict []byte
+
+//go:embed thaidict.gz
+var encodedThaiDict []byte
+
+var cjDict, thaiDict *Dictionary
+var loadCJDict, loadThaiDict sync.Once
+
+const (
+	// unknownCost is the cost of a character that isn't in a Dictionary.
+	unknownCost = 255
+
+	// maxKatakanaRun is the longest run of Katakana that's considered as a
+	// single, unknown word.
+	maxKatakanaRun = 20
+)
+
+// katakanaCosts are the costs of treating a run of Katakana of the given
+// length as a single word -- e.g., an unlisted loanword.
+var katakanaCosts = []int{8192, 984, 408, 240, 204, 252, 300, 372, 480}
+
+// A Dictionary is a list of words, each with a cost, that's used to segment
+// text written without spaces between its words.
+//
+// A word's cost is, roughly, the negative logarithm of its frequency:
+// segmentation picks the sequence of words with the lowest total cost, with
+// each character that isn't in the Dictionary costing 255.
+type Dictionary struct {
+	words    map[string]int
+	maxRunes int
+}
+
+// New creates a Dictionary from words, a map of words to costs.
+func New(words map[string]int) *Dictionary {
+	d := &Dictionary{words: make(map[string]int, len(words))}
+	for word, cost := range words {
+		d.add(word, cost)
+	}
+	return d
+}
+
+// Read reads a Dictionary from r.
+//
+// Each line holds a word, optionally followed by a tab and its cost; words
+// without a cost are given a cost of 1, so segmenting with them amounts to
+// maximum matching (i.e., using the fewest words). Blank lines are ignored.
+func Read(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{words: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		word, cost := line, 1
+		if i := strings.IndexByte(line, '\t'); i >= 0 {
+			c, err := strconv.Atoi(strings.TrimSpace(line[i+1:]))
+			if err != nil || c < 0 {
+				return nil, fmt.Errorf("dictionary: line %d: invalid cost %q", n, line[i+1:])
+			}
+			word, cost = line[:i], c
+		}
+		d.add(word, cost)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ChineseJapanese returns the built-in dictionary of Chinese and Japanese
+// words.
+func ChineseJapanese() *Dictionary {
+	loadCJDict.Do(func() {
+		cjDict = readEmbeddedDictionary(encodedCJDict)
+	})
+	return cjDict
+}
+
+// Thai returns the built-in dictionary of Thai words.
+func Thai() *Dictionary {
+	loadThaiDict.Do(func() {
+		thaiDict = readEmbeddedDictionary(encodedThaiDict)
+	})
+	return thaiDict
+}
+
+func readEmbeddedDictionary(data []byte) *Dictionary {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	d, err := Read(r)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Contains reports whether word is in the Dictionary.
+func (d *Dictionary) Contains(word string) bool {
+	_, found := d.words[word]
+	return found
+}
+
+// Len returns the number of words in the Dictionary.
+func (d *Dictionary) Len() int {
+	return len(d.words)
+}
+
+func (d *Dictionary) add(word string, cost int) {
+	if word == "" {
+		return
+	}
+	d.words[word] = cost
+	if n := utf8.RuneCountInString(word); n > d.maxRunes {
+		d.maxRunes = n
+	}
+}
+
+// Tokenizer splits text into words, using dictionaries to segment scripts
+// that are written without spaces (e.g., Chinese, Japanese and Thai).
+//
+// Text in other scripts is split at the word boundaries defined by UAX #29,
+// as with tokenize.UnicodeWordTokenizer.
+type Tokenizer struct {
+	words   *tokenize.UnicodeWordTokenizer
+	scripts []scriptDictionary
+}
+
+// A scriptDictionary assigns a Dictionary to the characters of a script.
+type scriptDictionary struct {
+	script *unicode.RangeTable
+	dict   func() *Dictionary
+}
+
+// TokenizerOptFunc configures a Tokenizer.
+type TokenizerOptFunc func(*Tokenizer)
+
+// UsingDictionary segments text in the given script (e.g., unicode.Thai)
+// using d, replacing any built-in dictionary for that script.
+func UsingDictionary(script *unicode.RangeTable, d *Dictionary) TokenizerOptFunc {
+	return func(tokenizer *Tokenizer) {
+		for i, s := range tokenizer.scripts {
+			if s.script == script {
+				tokenizer.scripts = append(tokenizer.scripts[:i], tokenizer.scripts[i+1:]...)
+				break
+			}
+		}
+		tokenizer.scripts = append([]scriptDictionary{
+			{script: script, dict: func() *Dictionary { return d }}}, tokenizer.scripts...)
+	}
+}
+
+// NewTokenizer is a Tokenizer constructor.
+//
+// By default, Han, Hiragana and Katakana text is segmented using
+// ChineseJapanese and Thai text using Thai. The built-in dictionaries are
+// only loaded once they're needed.
+func NewTokenizer(opts ...TokenizerOptFunc) *Tokenizer {
+	tok := &Tokenizer{words: tokenize.NewUnicodeWordTokenizer()}
+
+	tok.scripts = []scriptDictionary{
+		{script: unicode.Han, dict: ChineseJapanese},
+		{script: unicode.Hiragana, dict: ChineseJapanese},
+		{script: unicode.Katakana, dict: ChineseJapanese},
+		{script: unicode.Thai, dict: Thai},
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(tok)
+	}
+
+	return tok
+}
+
+// Tokenize splits text into a slice of words.
+func (t *Tokenizer) Tokenize(text string) []string {
+	words := []string{}
+	for _, tok := range t.Tokens(text) {
+		words = append(words, tok.Text)
+	}
+	return words
+}
+
+// Tokens splits text into a slice of word Tokens.
+//
+// As with tokenize.UnicodeWordTokenizer, whitespace and punctuation are
+// omitted.
+func (t *Tokenizer) Tokens(text string) []*tokenize.Token {
+	var tokens []*tokenize.Token
+
+	// Consecutive words that use the same Dictionary are collected into a
+	// run, which is then segmented as a whole.
+	var dict *Dictionary
+	var run tokenize.Span
+	flush := func() {
+		if dict != nil {
+			runes := run.RuneStart
+			last := run.Start
+			segmentRun(text[run.Start:run.End], dict, func(start, end int) {
+				start, end = run.Start+start, run.Start+end
+				runes += utf8.RuneCountInString(text[last:start])
+				n := utf8.RuneCountInString(text[start:end])
+				tokens = append(tokens, &tokenize.Token{
+					Text: text[start:end],
+					Span: tokenize.Span{
+						Start: start, End: end, RuneStart: runes, RuneEnd: runes + n},
+					Kind: tokenize.Classify(text[start:end])})
+				runes, last = runes+n, end
+			})
+			dict = nil
+		}
+	}
+
+	for _, tok := range t.words.Tokens(text) {
+		d := t.dictionaryFor(tok.Text)
+		if d == nil || d != dict || tok.Span.Start != run.End {
+			flush()
+		}
+		if d == nil {
+			tokens = append(tokens, tok)
+			continue
+		}
+		if dict == nil {
+			dict, run = d, tok.Span
+		}
+		run.End, run.RuneEnd = tok.Span.End, tok.Span.RuneEnd
+	}
+	flush()
+
+	return tokens
+}
+
+// dictionaryFor returns the Dictionary used by all of segment's characters,
+// if any. Combining marks and the prolonged sound mark ("ー") use the
+// Dictionary of the text around them.
+func (t *Tokenizer) dictionaryFor(segment string) *Dictionary {
+	var found *Dictionary
+	for _, r := range segment {
+		if isAttached(r) {
+			continue
+		}
+		var d *Dictionary
+		for _, s := range t.scripts {
+			if unicode.Is(s.script, r) {
+				d = s.dict()
+				break
+			}
+		}
+		if d == nil || (found != nil && found != d) {
+			return nil
+		}
+		found = d
+	}
+	return found
+}
+
+// isAttached reports whether r belongs to the script of the text around it.
+func isAttached(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) || r == 'ー'
+}
+
+func isKatakana(r rune) bool {
+	return unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+// segmentRun splits run into the sequence of words with the lowest total
+// cost according to d, calling emit with the byte offsets of each word.
+//
+// Characters that aren't in d are kept with any combining marks that follow
+// them, and consecutive unknown characters are combined into a single word.
+func segmentRun(run string, d *Dictionary, emit func(int, int)) {
+	// units are the offsets of each character, along with its marks.
+	units := []int{}
+	for i, r := range run {
+		if i == 0 || !isAttached(r) {
+			units = append(units, i)
+		}
+	}
+	units = append(units, len(run))
+	n := len(units) - 1
+
+	// best[i] is the lowest cost of segmenting the first i units, which is
+	// achieved by ending a word at unit i that began at unit from[i].
+	best := make([]int, n+1)
+	from := make([]int, n+1)
+	known := make([]bool, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = -1
+	}
+
+	relax := func(i, j, cost int, inDict bool) {
+		if best[j] < 0 || best[i]+cost < best[j] {
+			best[j], from[j], known[j] = best[i]+cost, i, inDict
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j <= n && j-i <= d.maxRunes; j++ {
+			if cost, found := d.words[run[units[i]:units[j]]]; found {
+				relax(i, j, cost, true)
+			}
+		}
+		relax(i, i+1, unknownCost, false)
+
+		// Consider an entire run of Katakana as a single word, since
+		// loanwords are often missing from the dictionary.
+		r, _ := utf8.DecodeRuneInString(run[units[i]:])
+		prev, _ := utf8.DecodeLastRuneInString(run[:units[i]])
+		if isKatakana(r) && (i == 0 || !isKatakana(prev)) {
+			j := i + 1
+			for j < n && j-i < maxKatakanaRun {
+				if next, _ := utf8.DecodeRuneInString(run[units[j]:]); !isKatakana(next) {
+					break
+				}
+				j++
+			}
+			if j-i < maxKatakanaRun {
+				cost := katakanaCosts[0]
+				if j-i < len(katakanaCosts) {
+					cost = katakanaCosts[j-i]
+				}
+				relax(i, j, cost, true)
+			}
+		}
+	}
+
+	// Walk back through the best segmentation, then emit its words in order,
+	// combining consecutive unknown units.
+	type word struct {
+		start, end int
+		known      bool
+	}
+	var words []word
+	for j := n; j > 0; j = from[j] {
+		words = append(words, word{start: from[j], end: j, known: known[j]})
+	}
+	for k := len(words) - 1; k >= 0; k-- {
+		w := words[k]
+		for !w.known && k > 0 && !words[k-1].known {
+			k--
+			w.end = words[k].end
+		}
+		emit(units[w.start], units[w.end])
+	}
+}
@@ -0,0 +1,71 @@
+package dictionary_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jdkato/twine/nlp/tokenize/dictionary"
+)
+
+func TestTokenizer(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{"我们在北京大学学习自然语言处理。",
+			[]string{"我们", "在", "北京", "大学", "学习", "自然", "语言", "处理"}},
+		{"私は東京大学で日本語を勉強しています。",
+			[]string{"私", "は", "東京", "大学", "で", "日本語", "を", "勉強", "し", "てい", "ます"}},
+		{"コンピューターサイエンスとインターネット",
+			[]string{"コンピューター", "サイエンス", "と", "インターネット"}},
+		{"ภาษาไทยเป็นภาษาที่ไม่มีการเว้นวรรคระหว่างคำ",
+			[]string{"ภาษา", "ไทย", "เป็น", "ภาษา", "ที่", "ไม่มี", "การ", "เว้น", "วรรค", "ระหว่าง", "คำ"}},
+		{"Hello 世界, this is mixed 日本語 text with ไทย too.",
+			[]string{"Hello", "世界", "this", "is", "mixed", "日本語", "text", "with", "ไทย", "too"}},
+	}
+
+	tok := dictionary.NewTokenizer()
+	for _, c := range cases {
+		last := 0
+		for _, token := range tok.Tokens(c.text) {
+			span := token.Span
+			if span.Start < last || span.End < span.Start || span.End > len(c.text) {
+				t.Fatalf("Tokenizer(): invalid span %v for %q", span, token.Text)
+			}
+			if c.text[span.Start:span.End] != token.Text {
+				t.Errorf("Tokenizer(): %q != %q", c.text[span.Start:span.End], token.Text)
+			}
+			if utf8.RuneCountInString(c.text[:span.Start]) != span.RuneStart ||
+				utf8.RuneCountInString(c.text[:span.End]) != span.RuneEnd {
+				t.Errorf("Tokenizer(): bad rune offsets %v for %q", span, token.Text)
+			}
+			last = span.Start
+		}
+		if actual := tok.Tokenize(c.text); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Tokenizer(%s): Actual: %q, Expected: %q", c.text, actual, c.expected)
+		}
+	}
+}
+
+func TestTokenizerCustom(t *testing.T) {
+	dict, err := dictionary.Read(strings.NewReader("กา\nกาแฟ\nแฟ\nร้อน\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok := dictionary.NewTokenizer(dictionary.UsingDictionary(unicode.Thai, dict))
+
+	// Maximum matching prefers "กาแฟ" to "กา" + "แฟ", and unknown characters
+	// are kept together.
+	expected := []string{"กาแฟ", "ร้อน", "มากๆ"}
+	if actual := tok.Tokenize("กาแฟร้อนมากๆ"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("TokenizerCustom(): Actual: %q, Expected: %q", actual, expected)
+	}
+
+	_, err = dictionary.Read(strings.NewReader("กาแฟ\tcheap\n"))
+	if err == nil {
+		t.Error("Read(): expected an error for an invalid cost")
+	}
+}
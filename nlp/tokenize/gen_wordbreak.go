@@ -0,0 +1,173 @@
+//go:build ignore
+
+// This program generates wordbreak_tables.go from the Unicode Character
+// Database. Run it with
+//
+//	go generate ./nlp/tokenize
+//
+// or, to use local copies of WordBreakProperty.txt and emoji-data.txt,
+//
+//	go run gen_wordbreak.go -ucd path/to/dir
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const version = "17.0.0"
+
+var ucd = flag.String("ucd", "", "directory containing the UCD files (defaults to unicode.org)")
+var output = flag.String("output", "wordbreak_tables.go", "output file")
+
+// properties maps Word_Break property values to their constants. Format is
+// merged into Extend, since the algorithm never distinguishes them.
+var properties = map[string]string{
+	"CR":                 "wbCR",
+	"LF":                 "wbLF",
+	"Newline":            "wbNewline",
+	"Extend":             "wbExtend",
+	"Format":             "wbExtend",
+	"ZWJ":                "wbZWJ",
+	"Regional_Indicator": "wbRegionalIndicator",
+	"Katakana":           "wbKatakana",
+	"Hebrew_Letter":      "wbHebrewLetter",
+	"ALetter":            "wbALetter",
+	"Single_Quote":       "wbSingleQuote",
+	"Double_Quote":       "wbDoubleQuote",
+	"MidNumLet":          "wbMidNumLet",
+	"MidLetter":          "wbMidLetter",
+	"MidNum":             "wbMidNum",
+	"Numeric":            "wbNumeric",
+	"ExtendNumLet":       "wbExtendNumLet",
+	"WSegSpace":          "wbWSegSpace",
+}
+
+type entry struct {
+	lo, hi uint64
+	value  string
+}
+
+func open(dir, file string) io.ReadCloser {
+	if *ucd != "" {
+		f, err := os.Open(filepath.Join(*ucd, file))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return f
+	}
+	url := fmt.Sprintf("https://www.unicode.org/Public/%s/ucd/%s%s", version, dir, file)
+	resp, err := http.Get(url)
+	if err != nil {
+		log.Fatal(err)
+	} else if resp.StatusCode != http.StatusOK {
+		log.Fatalf("%s: %s", url, resp.Status)
+	}
+	return resp.Body
+}
+
+// parse reads the entries of a UCD file, keeping those that keep accepts.
+func parse(r io.Reader, keep func(string) (string, bool)) []entry {
+	var entries []entry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		value, found := keep(strings.TrimSpace(fields[1]))
+		if !found {
+			continue
+		}
+
+		codes := strings.Split(strings.TrimSpace(fields[0]), "..")
+		lo, err := strconv.ParseUint(codes[0], 16, 32)
+		if err != nil {
+			log.Fatal(err)
+		}
+		hi := lo
+		if len(codes) > 1 {
+			if hi, err = strconv.ParseUint(codes[1], 16, 32); err != nil {
+				log.Fatal(err)
+			}
+		}
+		entries = append(entries, entry{lo: lo, hi: hi, value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Merge adjacent ranges with the same value.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].lo < entries[j].lo })
+	merged := []entry{}
+	for _, e := range entries {
+		n := len(merged)
+		if n > 0 && merged[n-1].value == e.value && merged[n-1].hi+1 == e.lo {
+			merged[n-1].hi = e.hi
+		} else {
+			merged = append(merged, e)
+		}
+	}
+
+	return merged
+}
+
+func main() {
+	flag.Parse()
+
+	f := open("auxiliary/", "WordBreakProperty.txt")
+	props := parse(f, func(value string) (string, bool) {
+		name, found := properties[value]
+		return name, found
+	})
+	f.Close()
+
+	f = open("emoji/", "emoji-data.txt")
+	pictographs := parse(f, func(value string) (string, bool) {
+		return "", value == "Extended_Pictographic"
+	})
+	f.Close()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_wordbreak.go from Unicode %s; DO NOT EDIT.\n\n", version)
+	fmt.Fprintf(&buf, "package tokenize\n\n")
+
+	fmt.Fprintf(&buf, "// wordBreakRanges are the code points whose Word_Break property isn't Other.\n")
+	fmt.Fprintf(&buf, "var wordBreakRanges = []wordBreakRange{\n")
+	for _, e := range props {
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %s},\n", e.lo, e.hi, e.value)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// pictographicRanges are the code points with the Extended_Pictographic\n")
+	fmt.Fprintf(&buf, "// property.\n")
+	fmt.Fprintf(&buf, "var pictographicRanges = []wordBreakRange{\n")
+	for _, e := range pictographs {
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, wbOther},\n", e.lo, e.hi)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
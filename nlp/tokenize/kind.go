@@ -0,0 +1,138 @@
+package tokenize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// A Kind identifies the type of content a Token holds.
+type Kind int
+
+// The kinds of Token produced by the tokenizers in this package.
+const (
+	Other        Kind = iota // Anything not covered by another kind.
+	Word                     // A sequence containing at least one letter.
+	Number                   // A sequence of digits, possibly with separators.
+	Punct                    // A sequence of punctuation characters.
+	Symbol                   // A sequence of symbols (e.g., "$" or "+").
+	URL                      // A web address (e.g., "https://example.com").
+	Email                    // An email address (e.g., "jane@example.com").
+	Path                     // A file path (e.g., "/usr/bin" or "src/main.go").
+	Version                  // A version number (e.g., "v1.2" or "2.0.1-rc1").
+	Hashtag                  // A hashtag (e.g., "#golang").
+	Mention                  // An @-mention (e.g., "@twitter").
+	Emoticon                 // An emoticon (e.g., ":-)").
+	Abbreviation             // An abbreviation (e.g., "Mr." or "i.e.").
+	Code                     // Source code (e.g., a Markdown code span).
+)
+
+var kindNames = map[Kind]string{
+	Other:        "Other",
+	Word:         "Word",
+	Number:       "Number",
+	Punct:        "Punct",
+	Symbol:       "Symbol",
+	URL:          "URL",
+	Email:        "Email",
+	Path:         "Path",
+	Version:      "Version",
+	Hashtag:      "Hashtag",
+	Mention:      "Mention",
+	Emoticon:     "Emoticon",
+	Abbreviation: "Abbreviation",
+	Code:         "Code",
+}
+
+// String returns the name of the Kind k.
+func (k Kind) String() string {
+	if name, found := kindNames[k]; found {
+		return name
+	}
+	return "Other"
+}
+
+// patterns for the kinds of tokens that should never be split.
+var reURL = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9+.-]*://|www\.|mailto:)\S*[^\s.,;:!?'"()\[\]{}<>]$`)
+var reEmail = regexp.MustCompile(`^[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.\p{L}{2,}$`)
+var rePath = regexp.MustCompile(`^(?:~|\.{1,2})?/\S*[^\s.,;:!?'"()\[\]{}<>]$|^[A-Za-z]:\\\S*[^\s.,;:!?'"()\[\]{}<>]$|^[\w.-]+(?:/[\w.-]+)+\.[A-Za-z]\w*$`)
+var reVersion = regexp.MustCompile(`^(?:[vV]\d+(?:\.\d+)*|\d+(?:\.\d+){2,})(?:[-+][0-9A-Za-z.-]*[0-9A-Za-z])?$`)
+var reHashtag = regexp.MustCompile(`^#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*$`)
+var reMention = regexp.MustCompile(`^@[\p{L}\p{N}_](?:[\p{L}\p{N}_.-]*[\p{L}\p{N}_])?$`)
+
+// Classify determines the Kind of the token text.
+//
+// Since tokenizers differ in how they split text, a multi-part kind (such as
+// a URL) is only detected if text contains the entire entity.
+func Classify(text string) Kind {
+	if _, found := emoticons[text]; found {
+		return Emoticon
+	} else if kind := classifyEntity(text); kind != Other {
+		return kind
+	} else if internalRE.MatchString(text) {
+		return Abbreviation
+	}
+	return classifyRunes(text)
+}
+
+// classifyEntity determines the Kind of text if it's a URL, email address,
+// file path, version number, hashtag, or mention, and returns Other
+// otherwise.
+func classifyEntity(text string) Kind {
+	if !strings.ContainsAny(strings.TrimRight(text, `.,;:!?'")]}`), `/\@#.`) {
+		// Every entity except short versions (e.g., "v2") contains at least
+		// one of these characters (ignoring trailing punctuation, which no
+		// entity ends with), so we can usually avoid the regex checks.
+		if len(text) > 1 && (text[0] == 'v' || text[0] == 'V') && reVersion.MatchString(text) {
+			return Version
+		}
+		return Other
+	}
+
+	switch {
+	case reURL.MatchString(text):
+		return URL
+	case reEmail.MatchString(text):
+		return Email
+	case reVersion.MatchString(text):
+		return Version
+	case rePath.MatchString(text):
+		return Path
+	case reHashtag.MatchString(text):
+		return Hashtag
+	case reMention.MatchString(text):
+		return Mention
+	}
+
+	return Other
+}
+
+// classifyRunes determines the Kind of text based on its characters.
+func classifyRunes(text string) Kind {
+	letters, digits, puncts, symbols := 0, 0, 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsPunct(r):
+			puncts++
+		case unicode.IsSymbol(r):
+			symbols++
+		}
+	}
+
+	switch {
+	case letters > 0:
+		return Word
+	case digits > 0:
+		return Number
+	case symbols > 0:
+		return Symbol
+	case puncts > 0:
+		return Punct
+	}
+
+	return Other
+}
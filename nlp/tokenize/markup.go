@@ -0,0 +1,835 @@
+package tokenize
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MarkupTokenizer splits the prose of a Markdown or HTML document into
+// tokens, leaving its markup out.
+//
+// Markup is blanked out before the prose is tokenized, so every Span locates
+// its token within the original document. Code (code spans, code blocks and
+// the contents of <code> and <pre> elements) and link targets are returned as
+// single, opaque tokens of Kind Code and URL, respectively, unless they're
+// skipped; all other markup (e.g., tags, list markers and emphasis) is
+// omitted.
+type MarkupTokenizer struct {
+	prose    Tokenizer
+	markdown bool
+	skipCode bool
+	skipURLs bool
+}
+
+// MarkupTokenizerOptFunc configures a MarkupTokenizer.
+type MarkupTokenizerOptFunc func(*MarkupTokenizer)
+
+// UsingProseTokenizer splits prose using t rather than the default,
+// NewIterTokenizer.
+func UsingProseTokenizer(t Tokenizer) MarkupTokenizerOptFunc {
+	return func(tokenizer *MarkupTokenizer) {
+		tokenizer.prose = t
+	}
+}
+
+// SkippingCode omits code rather than returning it as opaque tokens.
+func SkippingCode() MarkupTokenizerOptFunc {
+	return func(tokenizer *MarkupTokenizer) {
+		tokenizer.skipCode = true
+	}
+}
+
+// SkippingURLs omits link targets rather than returning them as opaque
+// tokens.
+func SkippingURLs() MarkupTokenizerOptFunc {
+	return func(tokenizer *MarkupTokenizer) {
+		tokenizer.skipURLs = true
+	}
+}
+
+// NewMarkdownTokenizer is a MarkupTokenizer constructor for Markdown.
+//
+// It understands CommonMark, GitHub's extensions (tables, task lists,
+// strikethrough and footnotes), YAML front matter and embedded HTML.
+func NewMarkdownTokenizer(opts ...MarkupTokenizerOptFunc) *MarkupTokenizer {
+	return newMarkupTokenizer(true, opts)
+}
+
+// NewHTMLTokenizer is a MarkupTokenizer constructor for HTML.
+func NewHTMLTokenizer(opts ...MarkupTokenizerOptFunc) *MarkupTokenizer {
+	return newMarkupTokenizer(false, opts)
+}
+
+func newMarkupTokenizer(markdown bool, opts []MarkupTokenizerOptFunc) *MarkupTokenizer {
+	tok := &MarkupTokenizer{markdown: markdown}
+
+	for _, applyOpt := range opts {
+		applyOpt(tok)
+	}
+
+	if tok.prose == nil {
+		tok.prose = NewIterTokenizer()
+	}
+
+	return tok
+}
+
+// Tokenize splits text into a slice of strings.
+func (t *MarkupTokenizer) Tokenize(text string) []string {
+	return texts(t.Tokens(text))
+}
+
+// Tokens splits text into a slice of Tokens.
+func (t *MarkupTokenizer) Tokens(text string) []*Token {
+	m := newMarkup(text)
+	if t.markdown {
+		m.blocks()
+	}
+	m.inlines(t.markdown)
+
+	tokens := t.prose.Tokens(string(m.masked))
+	for _, tok := range m.opaque {
+		if tok.Kind == Code && !t.skipCode || tok.Kind != Code && !t.skipURLs {
+			tokens = append(tokens, tok)
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].Span.Start < tokens[j].Span.Start
+	})
+
+	// Rune offsets are counted in the original text, since blanking out a
+	// multi-byte character changes the number of runes before it.
+	counter := runeCounter{text: text}
+	for _, tok := range tokens {
+		tok.Span = counter.span(tok.Span.Start, tok.Span.End)
+	}
+
+	return tokens
+}
+
+// markup blanks out the markup of a document, collecting its opaque tokens.
+type markup struct {
+	text     string
+	masked   []byte
+	opaque   []*Token
+	labels   map[string]bool // the labels of link reference definitions
+	brackets map[int]int     // the offsets of matching brackets
+
+	// unterminated maps each emphasis delimiter to the end of the last
+	// paragraph in which a run of it was left unclosed.
+	unterminated map[byte]int
+
+	// unclosed records the HTML terminators (e.g., "-->") that don't appear
+	// in the rest of the document.
+	unclosed map[string]bool
+}
+
+func newMarkup(text string) *markup {
+	return &markup{text: text, masked: []byte(text),
+		labels: map[string]bool{}, unterminated: map[byte]int{}, unclosed: map[string]bool{}}
+}
+
+// mask replaces text[start:end], except for line endings, with spaces.
+func (m *markup) mask(start, end int) {
+	for i := start; i < end; i++ {
+		if m.masked[i] != '\n' && m.masked[i] != '\r' {
+			m.masked[i] = ' '
+		}
+	}
+}
+
+// emit records text[start:end], without surrounding whitespace, as an opaque
+// token.
+func (m *markup) emit(start, end int, kind Kind) {
+	for start < end && isSpaceByte(m.text[start]) {
+		start++
+	}
+	for end > start && isSpaceByte(m.text[end-1]) {
+		end--
+	}
+	if start < end {
+		m.opaque = append(m.opaque, &Token{
+			Text: m.text[start:end], Span: Span{Start: start, End: end}, Kind: kind})
+	}
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// A line locates a line of text, excluding its line ending.
+type line struct {
+	start, end int
+}
+
+func splitLines(text string) []line {
+	var lines []line
+	for start := 0; start < len(text); {
+		end, next := len(text), len(text)
+		if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end, next = start+i, start+i+1
+		}
+		if end > start && text[end-1] == '\r' {
+			end--
+		}
+		lines = append(lines, line{start: start, end: end})
+		start = next
+	}
+	return lines
+}
+
+// indentation returns the width of the whitespace at the start of s, with tab
+// stops every four columns.
+func indentation(s string) int {
+	width := 0
+	for _, c := range []byte(s) {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+var reFence = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+var reThematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,}|=+[ \t]*)$`)
+var reATXHeading = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]|$)`)
+var reATXClosing = regexp.MustCompile(`(?:^|[ \t])#+[ \t]*$`)
+var reListItem = regexp.MustCompile(`^[ \t]*(?:[-+*]|\d{1,9}[.)])(?:[ \t]+(?:\[[ xX]\](?:[ \t]+|$))?|$)`)
+var reReference = regexp.MustCompile(`^ {0,3}\[((?:[^\]\\^]|\\.)(?:[^\]\\]|\\.)*)\]:[ \t]*(?:<([^<>\n]*)>|(\S+))(?:[ \t]+(?:"[^"]*"|'[^']*'|\([^)]*\)))?[ \t]*$`)
+var reFootnote = regexp.MustCompile(`^ {0,3}\[\^[^\]\s]+\]:?`)
+var reTableDelimiter = regexp.MustCompile(`^[ \t]*\|?(?:[ \t]*:?-+:?[ \t]*\|)*[ \t]*:?-+:?[ \t]*\|?[ \t]*$`)
+
+// blocks blanks out Markdown's block-level markup and code blocks.
+func (m *markup) blocks() {
+	lines := splitLines(m.text)
+
+	i := 0
+	if len(lines) > 0 && m.line(lines[0], 0) == "---" {
+		// YAML front matter.
+		for j := 1; j < len(lines); j++ {
+			if end := m.line(lines[j], 0); end == "---" || end == "..." {
+				m.mask(0, lines[j].end)
+				i = j + 1
+				break
+			}
+		}
+	}
+
+	blank, list, table := true, false, false
+	for ; i < len(lines); i++ {
+		l := lines[i]
+		start, depth := m.quotes(l, -1)
+		content := m.text[start:l.end]
+		indent := indentation(content)
+
+		if strings.TrimSpace(content) == "" {
+			blank, table = true, false
+			continue
+		} else if blank && indent == 0 {
+			list = false
+		}
+
+		switch {
+		case indent >= 4 && blank && !list:
+			i = m.indentedCode(lines, i, depth)
+		case isFence(content):
+			i = m.fencedCode(lines, i, depth)
+		case reThematicBreak.MatchString(content):
+			m.mask(start, l.end)
+		case reATXHeading.MatchString(content):
+			m.heading(start, l.end)
+		case reReference.MatchString(content):
+			m.reference(start, l.end)
+		default:
+			if loc := reFootnote.FindStringIndex(content); loc != nil {
+				m.mask(start, start+loc[1])
+			} else if loc := reListItem.FindStringIndex(content); loc != nil {
+				m.mask(start, start+loc[1])
+				list = true
+			}
+			if !table && strings.Contains(content, "|") && i+1 < len(lines) {
+				next, _ := m.quotes(lines[i+1], depth)
+				table = isTableDelimiter(m.text[next:lines[i+1].end])
+			}
+			if table {
+				m.tableRow(start, l.end)
+			}
+		}
+
+		blank = false
+	}
+}
+
+// isFence reports whether s opens a fenced code block.
+func isFence(s string) bool {
+	if !reFence.MatchString(s) {
+		return false
+	}
+	s = strings.TrimLeft(s, " ")
+	return s[0] == '~' || !strings.Contains(strings.TrimLeft(s, "`"), "`")
+}
+
+// line returns the text of l, starting at offset, without trailing
+// whitespace.
+func (m *markup) line(l line, offset int) string {
+	return strings.TrimRight(m.text[l.start+offset:l.end], " \t")
+}
+
+// quotes blanks out up to max (or, if max is negative, all) of the block
+// quote markers at the start of l, returning the offset of the rest of l and
+// the number of markers.
+func (m *markup) quotes(l line, max int) (int, int) {
+	start, depth := l.start, 0
+	for depth != max {
+		i := start
+		for i < l.end && i-start < 3 && m.text[i] == ' ' {
+			i++
+		}
+		if i == l.end || m.text[i] != '>' {
+			break
+		}
+		i++
+		if i < l.end && (m.text[i] == ' ' || m.text[i] == '\t') {
+			i++
+		}
+		m.mask(start, i)
+		start = i
+		depth++
+	}
+	return start, depth
+}
+
+// indentedCode records the indented code block that starts at lines[i],
+// returning the index of its last line.
+func (m *markup) indentedCode(lines []line, i, depth int) int {
+	last := i
+	for j := i; j < len(lines); j++ {
+		start, _ := m.quotes(lines[j], depth)
+		content := m.text[start:lines[j].end]
+		if strings.TrimSpace(content) == "" {
+			continue
+		} else if indentation(content) < 4 {
+			break
+		}
+		last = j
+	}
+
+	m.emit(lines[i].start, lines[last].end, Code)
+	m.mask(lines[i].start, lines[last].end)
+	return last
+}
+
+// fencedCode records the fenced code block that starts at lines[i], returning
+// the index of its last line.
+func (m *markup) fencedCode(lines []line, i, depth int) int {
+	start, _ := m.quotes(lines[i], depth)
+	fence := strings.TrimLeft(m.line(lines[i], start-lines[i].start), " ")
+	fence = fence[:len(fence)-len(strings.TrimLeft(fence, fence[:1]))]
+
+	last := len(lines) - 1
+	end := lines[last].end
+	for j := i + 1; j < len(lines); j++ {
+		start, _ := m.quotes(lines[j], depth)
+		closing := strings.TrimLeft(m.line(lines[j], start-lines[j].start), " ")
+		if indentation(m.text[start:lines[j].end]) < 4 && strings.HasPrefix(closing, fence) &&
+			strings.Trim(closing, fence[:1]) == "" {
+			last, end = j, lines[j].start
+			break
+		}
+	}
+
+	if i < len(lines)-1 {
+		m.emit(lines[i+1].start, end, Code)
+	}
+	m.mask(lines[i].start, lines[last].end)
+	return last
+}
+
+// heading blanks out the markers of the ATX heading text[start:end].
+func (m *markup) heading(start, end int) {
+	content := m.text[start:end]
+	open := strings.IndexByte(content, '#')
+	marker := open + len(content[open:]) - len(strings.TrimLeft(content[open:], "#"))
+	m.mask(start, start+marker)
+
+	if loc := reATXClosing.FindStringIndex(content[marker:]); loc != nil {
+		m.mask(start+marker+loc[0], end)
+	}
+}
+
+// reference blanks out the link reference definition text[start:end],
+// recording its label and destination.
+func (m *markup) reference(start, end int) {
+	loc := reReference.FindStringSubmatchIndex(m.text[start:end])
+	m.labels[normalizeLabel(m.text[start+loc[2]:start+loc[3]])] = true
+	if loc[4] >= 0 {
+		m.emit(start+loc[4], start+loc[5], URL)
+	} else {
+		m.emit(start+loc[6], start+loc[7], URL)
+	}
+	m.mask(start, end)
+}
+
+// normalizeLabel case-folds a link label and collapses its whitespace.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+func isTableDelimiter(s string) bool {
+	return strings.Contains(s, "|") && reTableDelimiter.MatchString(s)
+}
+
+// tableRow blanks out the cell delimiters of the table row text[start:end].
+func (m *markup) tableRow(start, end int) {
+	if isTableDelimiter(m.text[start:end]) {
+		m.mask(start, end)
+		return
+	}
+	for i := start; i < end; i++ {
+		if m.text[i] == '\\' {
+			i++
+		} else if m.text[i] == '|' {
+			m.mask(i, i+1)
+		}
+	}
+}
+
+// A linkEnd is the part of a link following its text -- e.g., "](url)" --
+// which starts at the offset at and ends at end.
+type linkEnd struct {
+	at, end   int
+	dest, lim int // the link target is text[dest:lim]
+}
+
+// inlines blanks out inline markup: HTML and, for Markdown, code spans,
+// links, emphasis and backslash escapes.
+func (m *markup) inlines(markdown bool) {
+	src := string(m.masked)
+
+	// Inline spans can't cross the blank lines between paragraphs, so para
+	// is the text up to the end of the current paragraph.
+	var breaks []int
+	for _, l := range splitLines(src) {
+		if strings.TrimSpace(src[l.start:l.end]) == "" {
+			breaks = append(breaks, l.start)
+		}
+	}
+	para := src[:0]
+	if markdown {
+		m.brackets = matchBrackets(src, breaks)
+	}
+
+	var ends []linkEnd
+	for i := 0; i < len(src); {
+		if i >= len(para) {
+			para = src
+			if k := sort.SearchInts(breaks, i+1); k < len(breaks) {
+				para = src[:breaks[k]]
+			}
+		}
+
+		if n := len(ends); n > 0 && ends[n-1].at <= i {
+			e := ends[n-1]
+			ends = ends[:n-1]
+			m.emit(e.dest, e.lim, URL)
+			m.mask(e.at, e.end)
+			if e.end > i {
+				i = e.end
+			}
+			continue
+		} else if m.masked[i] != src[i] {
+			// This is the closing delimiter of a span that's already been
+			// blanked out.
+			i++
+			continue
+		}
+
+		c := src[i]
+		switch {
+		case c == '<':
+			i = m.angle(src, i, markdown)
+		case !markdown:
+			i++
+		case c == '\\' && i+1 < len(src) && isASCIIPunct(src[i+1]):
+			m.mask(i, i+1)
+			i += 2
+		case c == '`':
+			i = m.codeSpan(para, i)
+		case c == '[' || c == '!' && strings.HasPrefix(src[i+1:], "["):
+			open := strings.IndexByte(src[i:], '[') + i + 1
+			if e, found := m.link(para, open); found {
+				ends = append(ends, e)
+				m.mask(i, open)
+				i = open
+			} else {
+				i = open
+			}
+		case c == '*' || c == '_' || c == '~':
+			i = m.emphasis(para, i)
+		default:
+			i++
+		}
+	}
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+// backticks returns the offset of the next run of exactly n backticks in src
+// at or after i, or -1 if there isn't one.
+func backticks(src string, i, n int) int {
+	for i < len(src) {
+		j := strings.IndexByte(src[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		start := i + j
+		end := start
+		for end < len(src) && src[end] == '`' {
+			end++
+		}
+		if end-start == n {
+			return start
+		}
+		i = end
+	}
+	return -1
+}
+
+// codeSpan records the code span starting at src[i], if any, returning the
+// offset at which to continue.
+func (m *markup) codeSpan(src string, i int) int {
+	n := 0
+	for i+n < len(src) && src[i+n] == '`' {
+		n++
+	}
+
+	close := backticks(src, i+n, n)
+	if close < 0 {
+		return i + n
+	}
+
+	m.emit(i+n, close, Code)
+	m.mask(i, close+n)
+	return close + n
+}
+
+var reFootnoteRef = regexp.MustCompile(`^\^[^\]\s]+\]`)
+
+// link finds the end of the link or image whose text starts at src[open].
+func (m *markup) link(src string, open int) (linkEnd, bool) {
+	if loc := reFootnoteRef.FindStringIndex(src[open:]); loc != nil {
+		return linkEnd{at: open, end: open + loc[1]}, true
+	} else if close, found := m.brackets[open-1]; found {
+		return m.linkTarget(src, open, close)
+	}
+	return linkEnd{}, false
+}
+
+// matchBrackets pairs each "[" in src with the "]" that closes it, if any,
+// ignoring escaped brackets and those in code spans. Brackets can't be paired
+// across the paragraph breaks at the offsets in breaks.
+func matchBrackets(src string, breaks []int) map[int]int {
+	matches := map[int]int{}
+
+	var open []int
+	para := src[:0]
+	for j := 0; j < len(src); j++ {
+		if j >= len(para) {
+			open, para = open[:0], src
+			if k := sort.SearchInts(breaks, j+1); k < len(breaks) {
+				para = src[:breaks[k]]
+			}
+		}
+
+		switch src[j] {
+		case '\\':
+			j++
+		case '`':
+			n := 0
+			for j+n < len(para) && para[j+n] == '`' {
+				n++
+			}
+			if close := backticks(para, j+n, n); close >= 0 {
+				j = close + n - 1
+			} else {
+				j += n - 1
+			}
+		case '[':
+			open = append(open, j)
+		case ']':
+			if n := len(open); n > 0 {
+				matches[open[n-1]] = j
+				open = open[:n-1]
+			}
+		}
+	}
+
+	return matches
+}
+
+// linkTarget parses what follows the text of a link, src[open:at] -- i.e., an
+// inline target (e.g., "(url)"), a reference (e.g., "[label]") or nothing, if
+// the text itself is a known label.
+func (m *markup) linkTarget(src string, open, at int) (linkEnd, bool) {
+	rest := src[at+1:]
+	switch {
+	case strings.HasPrefix(rest, "("):
+		return inlineTarget(src, at)
+	case strings.HasPrefix(rest, "["):
+		if end := strings.IndexByte(rest, ']'); end >= 0 {
+			return linkEnd{at: at, end: at + 1 + end + 1}, true
+		}
+	case m.labels[normalizeLabel(src[open:at])]:
+		return linkEnd{at: at, end: at + 1}, true
+	}
+	return linkEnd{}, false
+}
+
+// maxLinkParens is the deepest nesting of parentheses allowed in a link
+// target, as in CommonMark's reference implementation.
+const maxLinkParens = 32
+
+// inlineTarget parses an inline link target, such as "](url "title")",
+// starting at src[at].
+func inlineTarget(src string, at int) (linkEnd, bool) {
+	i := at + 2
+	skip := func() {
+		for i < len(src) && isSpaceByte(src[i]) {
+			i++
+		}
+	}
+
+	skip()
+	e := linkEnd{at: at, dest: i, lim: i}
+	if i < len(src) && src[i] == '<' {
+		end := strings.IndexAny(src[i+1:], "<>\n")
+		if end < 0 || src[i+1+end] != '>' {
+			return linkEnd{}, false
+		}
+		e.dest, e.lim = i+1, i+1+end
+		i = e.lim + 1
+	} else {
+		depth := 0
+		for ; i < len(src) && !isSpaceByte(src[i]); i++ {
+			if src[i] == '\\' {
+				i++
+			} else if src[i] == '(' {
+				if depth++; depth > maxLinkParens {
+					return linkEnd{}, false
+				}
+			} else if src[i] == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+		if i > len(src) {
+			return linkEnd{}, false
+		}
+		e.lim = i
+	}
+
+	skip()
+	if i < len(src) && strings.IndexByte(`"'(`, src[i]) >= 0 {
+		delim := src[i]
+		if delim == '(' {
+			delim = ')'
+		}
+		end := strings.IndexByte(src[i+1:], delim)
+		if end < 0 {
+			return linkEnd{}, false
+		}
+		i += end + 2
+		skip()
+	}
+
+	if i >= len(src) || src[i] != ')' {
+		return linkEnd{}, false
+	}
+	e.end = i + 1
+	return e, true
+}
+
+// emphasis blanks out the run of emphasis (or strikethrough) delimiters
+// starting at src[i], along with the run that closes it, if any. It returns
+// the offset at which to continue.
+//
+// src ends with the current paragraph.
+func (m *markup) emphasis(src string, i int) int {
+	c := src[i]
+	end := i
+	for end < len(src) && src[end] == c {
+		end++
+	}
+
+	if opens, _ := flanking(src, i, end); !opens || c == '~' && end-i > 2 {
+		return end
+	} else if c != '~' && m.unterminated[c] == len(src) {
+		// An earlier run in this paragraph found nothing to close it, so
+		// neither will this one.
+		return end
+	}
+
+	// Find the closest unused run of the same delimiter that can close this
+	// one.
+	for j := end; j < len(src); {
+		k := strings.IndexByte(src[j:], c)
+		if k < 0 {
+			break
+		}
+		start := j + k
+		j = start
+		for j < len(src) && src[j] == c {
+			j++
+		}
+		if m.masked[start] != c {
+			continue
+		} else if _, closes := flanking(src, start, j); closes && (c != '~' || j-start == end-i) {
+			m.mask(i, end)
+			m.mask(start, j)
+			return end
+		}
+	}
+
+	m.unterminated[c] = len(src)
+	return end
+}
+
+// flanking reports whether the delimiter run src[start:end] can open and
+// close emphasis, according to CommonMark's rules.
+func flanking(src string, start, end int) (bool, bool) {
+	before, after := ' ', ' '
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(src[:start])
+	}
+	if end < len(src) {
+		after, _ = utf8.DecodeRuneInString(src[end:])
+	}
+
+	punct := func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }
+	left := !unicode.IsSpace(after) &&
+		(!punct(after) || unicode.IsSpace(before) || punct(before))
+	right := !unicode.IsSpace(before) &&
+		(!punct(before) || unicode.IsSpace(after) || punct(after))
+
+	if src[start] == '_' {
+		return left && (!right || punct(before)), right && (!left || punct(after))
+	}
+	return left, right
+}
+
+var reAutolink = regexp.MustCompile("^<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\\s<>]*|[A-Za-z0-9.!#$%&'*+/=?^_`{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>")
+var reTag = regexp.MustCompile("^</?([A-Za-z][A-Za-z0-9-]*)(?:\\s+[A-Za-z_:][\\w.:-]*(?:\\s*=\\s*(?:[^\\s\"'=<>`]+|'[^']*'|\"[^\"]*\"))?)*\\s*/?>")
+var reLinkAttr = regexp.MustCompile("(?i)\\s(?:href|src)\\s*=\\s*(?:\"([^\"]*)\"|'([^']*)'|([^\\s\"'=<>`]+))")
+
+// htmlSpecials are the kinds of HTML markup that end with a fixed string.
+var htmlSpecials = [][2]string{
+	{"<!--", "-->"}, {"<![CDATA[", "]]>"}, {"<?", "?>"}, {"<!", ">"}}
+
+// An element is an HTML element whose content isn't prose.
+type element struct {
+	code    bool           // whether its content is code
+	closing *regexp.Regexp // its closing tag
+}
+
+func newElement(name string, code bool) element {
+	return element{code: code, closing: regexp.MustCompile(`(?i)</` + name + `\s*>`)}
+}
+
+var elements = map[string]element{
+	"code":     newElement("code", true),
+	"kbd":      newElement("kbd", true),
+	"pre":      newElement("pre", true),
+	"samp":     newElement("samp", true),
+	"script":   newElement("script", false),
+	"style":    newElement("style", false),
+	"textarea": newElement("textarea", false),
+}
+
+// angle blanks out the autolink or HTML markup starting at src[i], if any,
+// returning the offset at which to continue.
+func (m *markup) angle(src string, i int, markdown bool) int {
+	if markdown {
+		if loc := reAutolink.FindStringIndex(src[i:]); loc != nil {
+			kind := URL
+			if Classify(src[i+1:i+loc[1]-1]) == Email {
+				kind = Email
+			}
+			m.emit(i+1, i+loc[1]-1, kind)
+			m.mask(i, i+loc[1])
+			return i + loc[1]
+		}
+	}
+
+	for _, special := range htmlSpecials {
+		if !strings.HasPrefix(src[i:], special[0]) {
+			continue
+		} else if m.unclosed[special[1]] {
+			return i + 1
+		}
+		end := strings.Index(src[i+len(special[0]):], special[1])
+		if end < 0 {
+			m.unclosed[special[1]] = true
+			return i + 1
+		}
+		end += i + len(special[0]) + len(special[1])
+		m.mask(i, end)
+		return end
+	}
+
+	loc := reTag.FindStringSubmatchIndex(src[i:])
+	if loc == nil {
+		return i + 1
+	}
+	end := i + loc[1]
+	tag := src[i:end]
+
+	for _, attr := range reLinkAttr.FindAllStringSubmatchIndex(tag, -1) {
+		for g := 2; g < len(attr); g += 2 {
+			if attr[g] >= 0 {
+				m.emit(i+attr[g], i+attr[g+1], URL)
+			}
+		}
+	}
+	m.mask(i, end)
+
+	name := strings.ToLower(src[i+loc[2] : i+loc[3]])
+	e, found := elements[name]
+	if !found || tag[1] == '/' || strings.HasSuffix(tag, "/>") || m.unclosed["</"+name] {
+		return end
+	}
+
+	close := e.closing.FindStringIndex(src[end:])
+	if close == nil {
+		m.unclosed["</"+name] = true
+		return end
+	}
+
+	if e.code {
+		// A <pre> element usually wraps a <code> element.
+		start, lim := end, end+close[0]
+		inner := strings.TrimSpace(src[start:lim])
+		if loc := reTag.FindStringSubmatchIndex(inner); loc != nil &&
+			strings.EqualFold(inner[loc[2]:loc[3]], "code") && inner[1] != '/' {
+			if c := elements["code"].closing.FindAllStringIndex(inner, -1); len(c) > 0 &&
+				c[len(c)-1][1] == len(inner) {
+				offset := strings.Index(src[start:lim], inner) + start
+				start, lim = offset+loc[1], offset+c[len(c)-1][0]
+			}
+		}
+		m.emit(start, lim, Code)
+	}
+	m.mask(end, end+close[1])
+	return end + close[1]
+}
@@ -0,0 +1,425 @@
+package tokenize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Profile is a serializable configuration for the tokenizer returned by
+// NewIterTokenizer, which allows tokenization to be configured in a JSON or
+// YAML file rather than in Go code.
+//
+// Each field corresponds to one of the tokenizer's options. A field that's
+// omitted (or null) keeps the tokenizer's default, while an empty list
+// disables the option entirely -- e.g., "emoticons: []" means that no
+// emoticons are recognized.
+type Profile struct {
+	// Name identifies the profile (e.g., "social").
+	Name string `json:"name" yaml:"name"`
+
+	// Contractions are split from the end of a word (e.g., "n't").
+	Contractions []string `json:"contractions" yaml:"contractions"`
+
+	// SplitCases are split from a word, along with any text after them.
+	SplitCases []string `json:"split_cases" yaml:"split_cases"`
+
+	// Prefixes are single characters split from the start of a word.
+	Prefixes []string `json:"prefixes" yaml:"prefixes"`
+
+	// Suffixes are single characters split from the end of a word.
+	Suffixes []string `json:"suffixes" yaml:"suffixes"`
+
+	// Emoticons are never split (e.g., ":-)").
+	Emoticons []string `json:"emoticons" yaml:"emoticons"`
+
+	// Unsplittable are tokens that are never split (e.g., "C++").
+	Unsplittable []string `json:"unsplittable" yaml:"unsplittable"`
+
+	// SpecialPattern is a regular expression matching tokens that are never
+	// split (e.g., abbreviations).
+	SpecialPattern *string `json:"special_pattern" yaml:"special_pattern"`
+
+	// Replacements are applied to the text before it's split (e.g., to
+	// replace curly quotes with straight ones).
+	Replacements []Replacement `json:"replacements" yaml:"replacements"`
+
+	// WithoutSuffix discards the prefixes and suffixes instead of returning
+	// them as tokens.
+	WithoutSuffix bool `json:"without_suffix" yaml:"without_suffix"`
+}
+
+// profileFile is the stored form of a Profile, which omits the fields that
+// keep the tokenizer's defaults. (Neither encoding/json nor yaml.v3 omits a
+// nil slice without also omitting an empty one.)
+type profileFile struct {
+	Name           string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Contractions   *[]string      `json:"contractions,omitempty" yaml:"contractions,omitempty"`
+	SplitCases     *[]string      `json:"split_cases,omitempty" yaml:"split_cases,omitempty"`
+	Prefixes       *[]string      `json:"prefixes,omitempty" yaml:"prefixes,omitempty"`
+	Suffixes       *[]string      `json:"suffixes,omitempty" yaml:"suffixes,omitempty"`
+	Emoticons      *[]string      `json:"emoticons,omitempty" yaml:"emoticons,omitempty"`
+	Unsplittable   *[]string      `json:"unsplittable,omitempty" yaml:"unsplittable,omitempty"`
+	SpecialPattern *string        `json:"special_pattern,omitempty" yaml:"special_pattern,omitempty"`
+	Replacements   *[]Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+	WithoutSuffix  bool           `json:"without_suffix,omitempty" yaml:"without_suffix,omitempty"`
+}
+
+func optional[T any](list []T) *[]T {
+	if list == nil {
+		return nil
+	}
+	return &list
+}
+
+func (p Profile) file() profileFile {
+	return profileFile{
+		Name:           p.Name,
+		Contractions:   optional(p.Contractions),
+		SplitCases:     optional(p.SplitCases),
+		Prefixes:       optional(p.Prefixes),
+		Suffixes:       optional(p.Suffixes),
+		Emoticons:      optional(p.Emoticons),
+		Unsplittable:   optional(p.Unsplittable),
+		SpecialPattern: p.SpecialPattern,
+		Replacements:   optional(p.Replacements),
+		WithoutSuffix:  p.WithoutSuffix,
+	}
+}
+
+// MarshalJSON implements json.Marshaler, omitting the fields that keep the
+// tokenizer's defaults.
+func (p Profile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.file())
+}
+
+// MarshalYAML implements yaml.Marshaler, omitting the fields that keep the
+// tokenizer's defaults.
+func (p Profile) MarshalYAML() (interface{}, error) {
+	return p.file(), nil
+}
+
+// A Replacement replaces every occurrence of From with To.
+type Replacement struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// A ProfileError describes an invalid field of a Profile.
+type ProfileError struct {
+	Profile string // The Profile's name, if any.
+	Field   string // The invalid field (e.g., "suffixes[2]").
+	Problem string // What's wrong with the field.
+}
+
+func (e *ProfileError) Error() string {
+	if e.Profile != "" {
+		return fmt.Sprintf("tokenize: profile %q: %s: %s", e.Profile, e.Field, e.Problem)
+	}
+	return fmt.Sprintf("tokenize: profile: %s: %s", e.Field, e.Problem)
+}
+
+// The names of the built-in profiles.
+const (
+	EnglishProfile   = "english"
+	SocialProfile    = "social"
+	TechnicalProfile = "technical"
+)
+
+// BuiltinProfile returns a copy of the built-in Profile with the given name.
+//
+// The "english" profile, for prose, is the default configuration of
+// NewIterTokenizer; "social" adds informal contractions and common
+// emoticons; and "technical" splits code spans from the surrounding text
+// while keeping command-line flags and function calls together.
+func BuiltinProfile(name string) (*Profile, error) {
+	var p *Profile
+
+	switch name {
+	case EnglishProfile:
+		p = defaultProfile()
+	case SocialProfile:
+		p = defaultProfile()
+		p.Contractions = append(p.Contractions, "'ve", "'d")
+		for _, emoticon := range socialEmoticons {
+			if _, found := emoticons[emoticon]; !found {
+				p.Emoticons = append(p.Emoticons, emoticon)
+			}
+		}
+		sort.Strings(p.Emoticons)
+	case TechnicalProfile:
+		p = defaultProfile()
+		pattern := internalRE.String() + "|" + technicalRE
+		p.SpecialPattern = &pattern
+		p.Prefixes = append(p.Prefixes, "`")
+		p.Suffixes = append(p.Suffixes, "`")
+	default:
+		return nil, fmt.Errorf("tokenize: unknown profile %q", name)
+	}
+
+	p.Name = name
+	return p, nil
+}
+
+// BuiltinProfiles returns the names of the built-in profiles.
+func BuiltinProfiles() []string {
+	return []string{EnglishProfile, SocialProfile, TechnicalProfile}
+}
+
+// socialEmoticons are the emoticons added by the "social" profile.
+var socialEmoticons = []string{
+	":)", ":-(", ":(", ";)", ";-)", ":D", ":-D", ";D", ":p", ";p", ":'(",
+	":/", ":|", ":*", "<3", "</3", "XD", ":O", "^_^", "^^", "T_T", ">_<"}
+
+// technicalRE matches command-line flags (e.g., "--output=out.txt") and
+// function calls (e.g., "os.Exit()").
+const technicalRE = `^--?[A-Za-z][\w-]*(?:=[\w/:.-]*[\w/])?$|^[A-Za-z_][\w.]*\(\)$`
+
+func defaultProfile() *Profile {
+	pattern := internalRE.String()
+
+	p := &Profile{
+		Contractions:   append([]string{}, contractions...),
+		SplitCases:     []string{},
+		Prefixes:       append([]string{}, prefixes...),
+		Suffixes:       append([]string{}, suffixes...),
+		Unsplittable:   []string{},
+		SpecialPattern: &pattern,
+	}
+	for emoticon := range emoticons {
+		p.Emoticons = append(p.Emoticons, emoticon)
+	}
+	sort.Strings(p.Emoticons)
+	for i := 0; i < len(sanitizations); i += 2 {
+		p.Replacements = append(p.Replacements, Replacement{
+			From: sanitizations[i], To: sanitizations[i+1]})
+	}
+
+	return p
+}
+
+// Validate reports every problem with the Profile, joined into a single
+// error; each problem is a *ProfileError.
+func (p *Profile) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ProfileError{
+			Profile: p.Name, Field: field, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	nonEmpty := func(field string, values []string) {
+		for i, value := range values {
+			if strings.TrimSpace(value) == "" {
+				invalid(fmt.Sprintf("%s[%d]", field, i), "must not be empty")
+			}
+		}
+	}
+	nonEmpty("contractions", p.Contractions)
+	nonEmpty("split_cases", p.SplitCases)
+	nonEmpty("emoticons", p.Emoticons)
+	nonEmpty("unsplittable", p.Unsplittable)
+
+	// The tokenizer removes prefixes and suffixes one byte at a time.
+	single := func(field string, values []string) {
+		for i, value := range values {
+			if len(value) != 1 {
+				invalid(fmt.Sprintf("%s[%d]", field, i),
+					"%q must be a single ASCII character", value)
+			}
+		}
+	}
+	single("prefixes", p.Prefixes)
+	single("suffixes", p.Suffixes)
+
+	if p.SpecialPattern != nil {
+		if _, err := regexp.Compile(*p.SpecialPattern); err != nil {
+			invalid("special_pattern", "%v", err)
+		}
+	}
+
+	for i, r := range p.Replacements {
+		if r.From == "" {
+			invalid(fmt.Sprintf("replacements[%d].from", i), "must not be empty")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Options returns the option funcs that configure a tokenizer according to
+// the Profile, or an error if the Profile is invalid.
+func (p *Profile) Options() ([]TokenizerOptFunc, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var opts []TokenizerOptFunc
+	if p.Contractions != nil {
+		opts = append(opts, UsingContractions(p.Contractions))
+	}
+	if p.SplitCases != nil {
+		// NewIterTokenizer appends the contractions to the split cases, which
+		// mustn't overwrite the Profile's own slice.
+		n := len(p.SplitCases)
+		opts = append(opts, UsingSplitCases(p.SplitCases[:n:n]))
+	}
+	if p.Prefixes != nil {
+		opts = append(opts, UsingPrefixes(p.Prefixes))
+	}
+	if p.Suffixes != nil {
+		opts = append(opts, UsingSuffixes(p.Suffixes))
+	}
+	if p.Emoticons != nil {
+		emoticons := make(map[string]int, len(p.Emoticons))
+		for _, emoticon := range p.Emoticons {
+			emoticons[emoticon] = 1
+		}
+		opts = append(opts, UsingEmoticons(emoticons))
+	}
+	if p.Unsplittable != nil {
+		unsplittable := make(map[string]bool, len(p.Unsplittable))
+		for _, token := range p.Unsplittable {
+			unsplittable[token] = true
+		}
+		opts = append(opts, UsingIsUnsplittable(func(token string) bool {
+			return unsplittable[token]
+		}))
+	}
+	if p.SpecialPattern != nil {
+		opts = append(opts, UsingSpecialRE(regexp.MustCompile(*p.SpecialPattern)))
+	}
+	if p.Replacements != nil {
+		var pairs []string
+		for _, r := range p.Replacements {
+			pairs = append(pairs, r.From, r.To)
+		}
+		opts = append(opts, UsingReplacements(pairs...))
+	}
+	if p.WithoutSuffix {
+		opts = append(opts, WithoutSuffix())
+	}
+
+	return opts, nil
+}
+
+// NewTokenizer returns a tokenizer configured according to the Profile, or
+// an error if the Profile is invalid.
+func (p *Profile) NewTokenizer() (*iterTokenizer, error) {
+	opts, err := p.Options()
+	if err != nil {
+		return nil, err
+	}
+	return NewIterTokenizer(opts...), nil
+}
+
+// A ProfileFormat is a file format that a Profile can be stored in.
+type ProfileFormat int
+
+// The supported ProfileFormats.
+const (
+	JSON ProfileFormat = iota
+	YAML
+)
+
+// profileFormat determines a file's format from its extension.
+func profileFormat(path string) (ProfileFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, nil
+	case ".yaml", ".yml":
+		return YAML, nil
+	}
+	return JSON, fmt.Errorf("tokenize: %s: unknown profile format", path)
+}
+
+// ParseProfile parses and validates a Profile stored in the given format.
+//
+// Unknown fields are an error, so that a misspelled option isn't silently
+// ignored.
+func ParseProfile(data []byte, format ProfileFormat) (*Profile, error) {
+	p := new(Profile)
+
+	var err error
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(p)
+	case YAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(p)
+	default:
+		err = fmt.Errorf("unknown format %d", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tokenize: invalid profile: %w", err)
+	}
+
+	if err = p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Marshal encodes the Profile in the given format.
+func (p *Profile) Marshal(format ProfileFormat) ([]byte, error) {
+	switch format {
+	case JSON:
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case YAML:
+		return yaml.Marshal(p)
+	}
+	return nil, fmt.Errorf("tokenize: unknown profile format %d", format)
+}
+
+// LoadProfile reads and validates the Profile stored at path, whose format is
+// determined by its extension (".json", ".yaml" or ".yml").
+func LoadProfile(path string) (*Profile, error) {
+	format, err := profileFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := ParseProfile(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// SaveProfile writes p to path, in the format determined by its extension
+// (".json", ".yaml" or ".yml").
+func SaveProfile(path string, p *Profile) error {
+	format, err := profileFormat(path)
+	if err != nil {
+		return err
+	}
+
+	if err = p.Validate(); err != nil {
+		return err
+	}
+
+	data, err := p.Marshal(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -21,21 +21,15 @@ func NewRegexpTokenizer(pattern string, gaps, discard bool) *RegexpTokenizer {
 
 // Tokenize splits text into a slice of tokens according to its regexp pattern.
 func (r RegexpTokenizer) Tokenize(text string) []string {
-	var tokens []string
-	if r.gaps {
-		temp := r.regex.Split(text, -1)
-		if r.discard {
-			for _, s := range temp {
-				if s != "" {
-					tokens = append(tokens, s)
-				}
-			}
-		} else {
-			tokens = temp
-		}
-	} else {
-		tokens = r.regex.FindAllString(text, -1)
-	}
+	return texts(r.Tokens(text))
+}
+
+// Tokens splits text into a slice of Tokens according to its regexp pattern.
+//
+// When gaps is set, this follows the semantics of regexp.Regexp.Split: the
+// tokens are the (possibly empty) substrings between matches.
+func (r RegexpTokenizer) Tokens(text string) []*Token {
+	_, tokens := r.scanner()(text, true, false)
 	return tokens
 }
 
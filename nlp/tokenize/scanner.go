@@ -0,0 +1,307 @@
+package tokenize
+
+import (
+	"errors"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrTokenTooLong is returned by Scanner.Err when a single token (or, for a
+// RegexpTokenizer that splits on gaps, the text between two matches) doesn't
+// fit in the Scanner's buffer.
+var ErrTokenTooLong = errors.New("tokenize: token too long")
+
+const (
+	// MaxScanTokenSize is the default maximum size of the buffer used by a
+	// Scanner; see Scanner.Buffer.
+	MaxScanTokenSize = 64 * 1024
+
+	startBufSize  = 4096
+	maxEmptyReads = 100
+)
+
+// A splitFunc tokenizes data, the unconsumed input of a Scanner, returning the
+// number of bytes that it consumed and the tokens found within them. The
+// tokens' Spans are relative to data.
+//
+// When atEOF is false, a splitFunc must only consume input whose tokens can't
+// be affected by the input that follows it. full reports that data fills the
+// Scanner's largest buffer, so that unless the splitFunc consumes some of it,
+// scanning stops with ErrTokenTooLong.
+type splitFunc func(data string, atEOF, full bool) (int, []*Token)
+
+// A Scanner reads Tokens from an io.Reader.
+//
+// Scanners are created by the Scanner method of a tokenizer, and they produce
+// the same Tokens as calling the tokenizer's Tokens method on the reader's
+// entire contents -- including the Spans, which index into the stream as a
+// whole -- while only buffering the input that hasn't been consumed yet.
+//
+// Like bufio.Scanner, successive calls to Scan step through the tokens, and
+// scanning stops unrecoverably at EOF, at the first I/O error, or when a token
+// is too large to fit in the buffer.
+type Scanner struct {
+	r       io.Reader
+	split   splitFunc
+	buf     []byte
+	start   int // The first unconsumed byte in buf.
+	end     int // The end of the data in buf.
+	maxSize int
+	offset  int // The stream's byte offset of buf[start].
+	runes   int // The stream's rune offset of buf[start].
+	pending []*Token
+	token   *Token
+	scanned bool
+	eof     bool
+	done    bool
+	err     error
+}
+
+func newScanner(r io.Reader, split splitFunc) *Scanner {
+	return &Scanner{r: r, split: split, maxSize: MaxScanTokenSize}
+}
+
+// Scanner returns a Scanner that reads Tokens from r.
+//
+// Since this tokenizer never produces a token that spans whitespace, the
+// Scanner tokenizes its input one whitespace-delimited run at a time.
+func (t *iterTokenizer) Scanner(r io.Reader) *Scanner {
+	return newScanner(r, t.scan)
+}
+
+// Scanner returns a Scanner that reads Tokens from r.
+//
+// A match is only accepted once the Scanner has seen input past its end, so
+// the Scanner produces the same Tokens as Tokens provided that a match can be
+// recognized without looking ahead of it -- as is the case for patterns built
+// from runs of character classes, like those used by the constructors in this
+// package. Zero-width assertions at the start of the pattern (e.g., ^ or \b)
+// are evaluated relative to the end of the previous match.
+//
+// Unless the tokenizer splits on gaps, text that doesn't match is skipped
+// rather than buffered: once the buffer is full, the Scanner drops the text
+// before the match that it's waiting to finish, or, if there isn't one, the
+// first half of the buffer. A match must therefore fit in half of the buffer.
+func (r RegexpTokenizer) Scanner(rd io.Reader) *Scanner {
+	return newScanner(rd, r.scanner())
+}
+
+// Buffer sets the initial buffer to use when scanning and the maximum size of
+// buffer that may be allocated during scanning, as with bufio.Scanner.
+//
+// Buffer panics if it is called after scanning has started.
+func (s *Scanner) Buffer(buf []byte, max int) {
+	if s.scanned {
+		panic("tokenize: Buffer called after Scan")
+	}
+	s.buf = buf[0:cap(buf)]
+	s.maxSize = max
+}
+
+// Scan advances the Scanner to the next token, which will then be available
+// through the Token method. It returns false when there are no more tokens,
+// either by reaching the end of the input or an error.
+func (s *Scanner) Scan() bool {
+	s.scanned = true
+	for len(s.pending) == 0 {
+		if s.done {
+			s.token = nil
+			return false
+		}
+		if s.end > s.start || s.eof {
+			s.consume()
+		}
+		if len(s.pending) == 0 && !s.done {
+			s.fill()
+		}
+	}
+
+	s.token, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// Token returns the most recent token generated by a call to Scan.
+func (s *Scanner) Token() *Token {
+	return s.token
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// consume splits the buffered data, queuing the tokens that it finds.
+func (s *Scanner) consume() {
+	data := string(s.buf[s.start:s.end])
+	if !s.eof {
+		// Hold back a partial rune until the rest of it has been read.
+		data = data[:fullRunes(data)]
+	}
+
+	full := s.start == 0 && s.end == len(s.buf) && len(s.buf) >= s.maxSize
+	advance, tokens := s.split(data, s.eof, full)
+	for _, tok := range tokens {
+		tok.Span.Start += s.offset
+		tok.Span.End += s.offset
+		tok.Span.RuneStart += s.runes
+		tok.Span.RuneEnd += s.runes
+	}
+	s.pending = append(s.pending, tokens...)
+
+	s.start += advance
+	s.offset += advance
+	s.runes += utf8.RuneCountInString(data[:advance])
+	if s.eof {
+		s.done = true
+	}
+}
+
+// fullRunes returns the length of the longest prefix of data that doesn't end
+// with a partial rune.
+func fullRunes(data string) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRuneInString(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
+
+// fill reads more data into the buffer, making room for it if necessary.
+func (s *Scanner) fill() {
+	if s.start > 0 && (s.end == len(s.buf) || s.start > len(s.buf)/2) {
+		copy(s.buf, s.buf[s.start:s.end])
+		s.end -= s.start
+		s.start = 0
+	}
+
+	if s.end == len(s.buf) {
+		if len(s.buf) >= s.maxSize {
+			s.err = ErrTokenTooLong
+			s.done = true
+			return
+		}
+		size := len(s.buf) * 2
+		if size == 0 {
+			size = startBufSize
+		}
+		if size > s.maxSize {
+			size = s.maxSize
+		}
+		buf := make([]byte, size)
+		copy(buf, s.buf[s.start:s.end])
+		s.end -= s.start
+		s.start = 0
+		s.buf = buf
+	}
+
+	for reads := 0; ; {
+		n, err := s.r.Read(s.buf[s.end:])
+		s.end += n
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			s.eof = true
+			return
+		} else if n > 0 {
+			return
+		}
+		reads++
+		if reads >= maxEmptyReads {
+			s.err = io.ErrNoProgress
+			s.eof = true
+			return
+		}
+	}
+}
+
+// scan is the splitFunc used by the iterTokenizer's Scanner: it consumes data
+// up to (and including) its last whitespace character.
+func (t *iterTokenizer) scan(data string, atEOF, _ bool) (int, []*Token) {
+	advance := len(data)
+	if !atEOF {
+		advance = 0
+		for i := len(data); i > 0; {
+			r, size := utf8.DecodeLastRuneInString(data[:i])
+			if unicode.IsSpace(r) {
+				advance = i
+				break
+			}
+			i -= size
+		}
+	}
+	if advance == 0 {
+		return 0, nil
+	}
+	return advance, t.Tokens(data[:advance])
+}
+
+// scanner returns the splitFunc used by the RegexpTokenizer's Scanner, which
+// consumes data up to the end of the last match that doesn't touch the end of
+// the data (or, when the buffer is full of text without such a match, skips
+// text that can't be part of a token).
+func (r RegexpTokenizer) scanner() splitFunc {
+	// offset is the stream's offset of data; last is the stream's offset of
+	// the last match; and afterMatch records that data begins at the end of a
+	// match, in which case an empty match at its start isn't allowed.
+	offset, last, afterMatch := 0, 0, false
+
+	return func(data string, atEOF, full bool) (int, []*Token) {
+		var tokens []*Token
+
+		counter := runeCounter{text: data}
+		add := func(start, end int) {
+			if !r.gaps || !r.discard || start < end {
+				tok := data[start:end]
+				tokens = append(tokens, &Token{
+					Text: tok, Span: counter.span(start, end), Kind: Classify(tok)})
+			}
+		}
+
+		advance, pending := 0, -1
+		for _, loc := range r.regex.FindAllStringIndex(data, -1) {
+			if loc[1] == len(data) && !atEOF {
+				pending = loc[0]
+				break
+			} else if afterMatch && loc[1] == 0 {
+				continue
+			}
+			if !r.gaps {
+				add(loc[0], loc[1])
+			} else if offset+loc[1] != 0 {
+				add(advance, loc[0])
+			}
+			last = offset + loc[0]
+			advance = loc[1]
+			afterMatch = true
+		}
+
+		if atEOF {
+			if r.gaps && offset+len(data) == 0 && r.regex.String() != "" {
+				add(0, 0)
+			} else if r.gaps && last != offset+len(data) {
+				add(advance, len(data))
+			}
+			advance = len(data)
+		} else if full && advance == 0 && !r.gaps {
+			// Only matches are tokens, so the text before the one that's
+			// still being read (if any) can be dropped.
+			advance = pending
+			if advance < 0 {
+				advance = len(data) / 2
+				for advance > 0 && !utf8.RuneStart(data[advance]) {
+					advance--
+				}
+			}
+			afterMatch = afterMatch && advance == 0
+		}
+
+		offset += advance
+		return advance, tokens
+	}
+}
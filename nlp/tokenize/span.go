@@ -0,0 +1,87 @@
+package tokenize
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// A Span locates a token within the text it was extracted from.
+//
+// Offsets are half-open, so text[Start:End] is always the token's source --
+// even if the tokenizer has rewritten the token's Text (e.g., by sanitizing
+// curly quotes or by using Treebank-style quotes).
+type Span struct {
+	Start     int // The byte offset of the token's first byte.
+	End       int // The byte offset just past the token's last byte.
+	RuneStart int // The rune offset of the token's first rune.
+	RuneEnd   int // The rune offset just past the token's last rune.
+}
+
+// runeCounter converts byte offsets into rune offsets for a single string.
+//
+// It's optimized for offsets that are requested in non-decreasing order, which
+// is how every tokenizer produces them.
+type runeCounter struct {
+	text  string
+	bytes int
+	runes int
+}
+
+func (rc *runeCounter) at(offset int) int {
+	if offset < rc.bytes {
+		rc.bytes, rc.runes = 0, 0
+	}
+	rc.runes += utf8.RuneCountInString(rc.text[rc.bytes:offset])
+	rc.bytes = offset
+	return rc.runes
+}
+
+func (rc *runeCounter) span(start, end int) Span {
+	return Span{Start: start, End: end, RuneStart: rc.at(start), RuneEnd: rc.at(end)}
+}
+
+// texts returns the Text of each token.
+func texts(tokens []*Token) []string {
+	var words []string
+	for _, tok := range tokens {
+		words = append(words, tok.Text)
+	}
+	return words
+}
+
+// locate aligns words, the output of a tokenizer that may drop or rewrite
+// parts of its input, with their sources in text.
+//
+// Each word is searched for at or after the end of the previous one, with
+// Treebank-style opening and closing quotes also matching a plain double
+// quote (or, for an opening quote, two single quotes). Words that can't be found are given an empty Span at the current
+// position.
+func locate(text string, words []string) []*Token {
+	var tokens []*Token
+
+	counter := runeCounter{text: text}
+	cursor := 0
+	for _, word := range words {
+		start, size := -1, len(word)
+		if idx := strings.Index(text[cursor:], word); idx >= 0 {
+			start = cursor + idx
+		}
+		if word == "``" || word == "''" {
+			// Treebank-style quotes replace double quotes, and "``" also
+			// replaces an opening "''".
+			for _, quote := range []string{`"`, "''"} {
+				if idx := strings.Index(text[cursor:], quote); idx >= 0 && (start < 0 || cursor+idx < start) {
+					start, size = cursor+idx, len(quote)
+				}
+			}
+		}
+		if start < 0 || word == "" {
+			start, size = cursor, 0
+		}
+		cursor = start + size
+		tokens = append(tokens, &Token{
+			Text: word, Span: counter.span(start, cursor), Kind: Classify(word)})
+	}
+
+	return tokens
+}
@@ -13,18 +13,31 @@ import (
 // symbol.
 type Token struct {
 	Text string // The token's actual content.
+	Span Span   // The token's location in the original text.
+	Kind Kind   // The token's type (e.g., Word or Punct).
 }
 
+// A TokenTester reports whether or not a string satisfies some property.
 type TokenTester func(string) bool
 
+// A Tokenizer splits text into tokens.
+//
+// Every tokenizer in this package implements Tokenizer, so they may be used
+// interchangeably.
 type Tokenizer interface {
-	Tokenize(string) []*Token
+	// Tokens splits text into a slice of Tokens, each with its Span and Kind.
+	Tokens(text string) []*Token
+
+	// Tokenize splits text into a slice of strings -- i.e., the Text of each
+	// Token returned by Tokens.
+	Tokenize(text string) []string
 }
 
 // iterTokenizer splits a sentence into words.
 type iterTokenizer struct {
 	specialRE      *regexp.Regexp
 	sanitizer      *strings.Replacer
+	replacements   []string // The sanitizer's old, new pairs, if they're known.
 	contractions   []string
 	splitCases     []string
 	suffixes       []string
@@ -51,9 +64,23 @@ func UsingSpecialRE(x *regexp.Regexp) TokenizerOptFunc {
 }
 
 // Use the provided sanitizer.
+//
+// Since the sanitizer's replacements aren't known, mapping a Token's Span back
+// to text takes quadratic time in the length of any whitespace-delimited chunk
+// that it changes; UsingReplacements avoids this.
 func UsingSanitizer(x *strings.Replacer) TokenizerOptFunc {
 	return func(tokenizer *iterTokenizer) {
 		tokenizer.sanitizer = x
+		tokenizer.replacements = nil
+	}
+}
+
+// Use a sanitizer that makes the provided replacements, given as old, new
+// pairs as for strings.NewReplacer. The old strings must be non-empty.
+func UsingReplacements(pairs ...string) TokenizerOptFunc {
+	return func(tokenizer *iterTokenizer) {
+		tokenizer.sanitizer = strings.NewReplacer(pairs...)
+		tokenizer.replacements = pairs
 	}
 }
 
@@ -108,6 +135,7 @@ func NewIterTokenizer(opts ...TokenizerOptFunc) *iterTokenizer {
 	tok.isUnsplittable = func(_ string) bool { return false }
 	tok.prefixes = prefixes
 	tok.sanitizer = sanitizer
+	tok.replacements = sanitizations
 	tok.specialRE = internalRE
 	tok.suffixes = suffixes
 	tok.noSuffix = false
@@ -122,147 +150,283 @@ func NewIterTokenizer(opts ...TokenizerOptFunc) *iterTokenizer {
 	return tok
 }
 
-func addToken(s string, toks []string) []string {
+// piece is a token located within a single whitespace-delimited chunk of
+// (sanitized) text.
+type piece struct {
+	text       string
+	start, end int
+}
+
+func addToken(s string, offset int, toks []piece) []piece {
 	if strings.TrimSpace(s) != "" {
-		toks = append(toks, s)
+		toks = append(toks, piece{text: s, start: offset, end: offset + len(s)})
 	}
 	return toks
 }
 
 func (t *iterTokenizer) isSpecial(token string) bool {
 	_, found := t.emoticons[token]
-	return found || t.specialRE.MatchString(token) || t.isUnsplittable(token)
+	return found || t.specialRE.MatchString(token) || t.isUnsplittable(token) ||
+		classifyEntity(token) != Other
+}
+
+// classify determines the Kind of token, taking the tokenizer's own emoticons
+// into account.
+func (t *iterTokenizer) classify(token string) Kind {
+	if _, found := t.emoticons[token]; found {
+		return Emoticon
+	}
+	return Classify(token)
 }
 
-func (t *iterTokenizer) doSplit(token string) []string {
-	tokens := []string{}
-	suffs := []string{}
+func (t *iterTokenizer) doSplit(token string) []piece {
+	tokens := []piece{}
+	suffs := []piece{}
 
-	last := 0
+	offset, last := 0, 0
 	for token != "" && utf8.RuneCountInString(token) != last {
 		if t.isSpecial(token) {
 			// We've found a special case (e.g., an emoticon) -- so, we add it as a token without
 			// any further processing.
-			tokens = addToken(token, tokens)
+			tokens = addToken(token, offset, tokens)
 			break
 		}
 		last = utf8.RuneCountInString(token)
-		lower := strings.ToLower(token)
+		lower := toLower(token)
 		if internal.HasAnyPrefix(token, t.prefixes) {
 			// Remove prefixes -- e.g., $100 -> [$, 100].
-			tokens = addToken(string(token[0]), tokens)
+			tokens = addToken(token[:1], offset, tokens)
 			token = token[1:]
+			offset++
 		} else if idx := internal.HasAnyIndex(lower, t.splitCases); idx > -1 {
 			// Handle "they'll", "I'll", "Don't", "won't", amount($).
 			//
 			// they'll -> [they, 'll].
 			// don't -> [do, n't].
 			// amount($) -> [amount, (, $, )].
-			tokens = addToken(token[:idx], tokens)
+			tokens = addToken(token[:idx], offset, tokens)
 			token = token[idx:]
+			offset += idx
 		} else if internal.HasAnySuffix(token, t.suffixes) {
 			// Remove suffixes -- e.g., Well) -> [Well, )].
-			suffs = append([]string{string(token[len(token)-1])}, suffs...)
-			token = token[:len(token)-1]
+			n := len(token) - 1
+			suffs = append([]piece{{text: token[n:], start: offset + n, end: offset + n + 1}}, suffs...)
+			token = token[:n]
 		} else {
-			tokens = addToken(token, tokens)
+			tokens = addToken(token, offset, tokens)
 		}
 	}
 
 	return append(tokens, suffs...)
 }
 
-func (t *iterTokenizer) doSplitNoSuffix(token string) []string {
-	var tokens []string
+func (t *iterTokenizer) doSplitNoSuffix(token string) []piece {
+	var tokens []piece
 
-	last := 0
+	offset, last := 0, 0
 	for token != "" && utf8.RuneCountInString(token) != last {
 		if t.isSpecial(token) {
 			// We've found a special case (e.g., an emoticon) -- so, we add it as a token without
 			// any further processing.
-			tokens = addToken(token, tokens)
+			tokens = addToken(token, offset, tokens)
 			break
 		}
 		last = utf8.RuneCountInString(token)
-		lower := strings.ToLower(token)
+		lower := toLower(token)
 		if internal.HasAnyPrefix(token, t.prefixes) {
 			// Remove prefixes -- e.g., $100 -> [$, 100].
 			token = token[1:]
+			offset++
 		} else if idx := internal.HasAnyIndex(lower, t.splitCases); idx > -1 {
 			// Handle "they'll", "I'll", "Don't", "won't", amount($).
 			//
 			// they'll -> [they, 'll].
 			// don't -> [do, n't].
 			// amount($) -> [amount, (, $, )].
-			tokens = addToken(token[:idx], tokens)
+			tokens = addToken(token[:idx], offset, tokens)
 			token = token[idx:]
+			offset += idx
 		} else if internal.HasAnySuffix(token, t.suffixes) {
 			// Remove suffixes -- e.g., Well) -> [Well, )].
 			token = token[:len(token)-1]
 		} else {
-			tokens = addToken(token, tokens)
+			tokens = addToken(token, offset, tokens)
 		}
 	}
 
 	return tokens
 }
 
-// tokenize splits a sentence into a slice of words.
+// toLower returns token in lowercase, making sure that the result's byte
+// offsets line up with token's.
+//
+// strings.ToLower may change the length of its input (e.g., by replacing
+// invalid UTF-8), in which case only the ASCII letters are lowercased.
+func toLower(token string) string {
+	lower := strings.ToLower(token)
+	if len(lower) == len(token) {
+		return lower
+	}
+	b := []byte(token)
+	for i := range b {
+		b[i] = lowerASCII(b[i])
+	}
+	return string(b)
+}
+
+func (t *iterTokenizer) split(chunk string) []piece {
+	if t.noSuffix {
+		return t.doSplitNoSuffix(chunk)
+	}
+	return t.doSplit(chunk)
+}
+
+// Tokenize splits a sentence into a slice of words.
 func (t *iterTokenizer) Tokenize(text string) []string {
 	var tokens []string
+	t.tokenize(text, func(tok string, _, _ int) {
+		tokens = append(tokens, tok)
+	})
+	return tokens
+}
 
-	clean, white := t.sanitizer.Replace(text), false
-	length := len(clean)
+// Tokens splits a sentence into a slice of Tokens.
+//
+// The sanitizer is applied to each whitespace-delimited chunk of text
+// separately, so a Token's Text may differ from its source (e.g., "’" becomes
+// "'"), but its Span always indexes into text itself.
+func (t *iterTokenizer) Tokens(text string) []*Token {
+	var tokens []*Token
 
-	start, index := 0, 0
-	cache := map[string][]string{}
-	for index <= length {
-		uc, size := utf8.DecodeRuneInString(clean[index:])
-		if size == 0 {
-			break
-		} else if index == 0 {
-			white = unicode.IsSpace(uc)
+	counter := runeCounter{text: text}
+	t.tokenize(text, func(tok string, start, end int) {
+		tokens = append(tokens, &Token{
+			Text: tok, Span: counter.span(start, end), Kind: t.classify(tok)})
+	})
+
+	return tokens
+}
+
+// tokenize calls emit for each token in text, along with the byte offsets of
+// its source.
+func (t *iterTokenizer) tokenize(text string, emit func(string, int, int)) {
+	cache := map[string][]piece{}
+	sanitize := t.sanitizer.Replace(text) != text
+
+	forEachField(text, func(offset int, chunk string) {
+		clean := chunk
+		var offsets []int
+		if sanitize {
+			if t.replacements != nil {
+				clean, offsets = t.replace(chunk)
+			} else {
+				clean = t.sanitizer.Replace(chunk)
+			}
 		}
-		if unicode.IsSpace(uc) != white {
-			if start < index {
-				span := clean[start:index]
-				if toks, found := cache[span]; found {
-					tokens = append(tokens, toks...)
-				} else {
-					toks := []string{}
-					if t.noSuffix {
-						toks = t.doSplitNoSuffix(span)
-					} else {
-						toks = t.doSplit(span)
-					}
-					cache[span] = toks
-					tokens = append(tokens, toks...)
+		forEachField(clean, func(base int, span string) {
+			pieces, found := cache[span]
+			if !found {
+				pieces = t.split(span)
+				cache[span] = pieces
+			}
+			from := 0
+			for _, p := range pieces {
+				start, end := base+p.start, base+p.end
+				if offsets != nil {
+					start, end = offsets[start], offsets[end]
+				} else if clean != chunk {
+					start = t.unsanitize(chunk, clean, start, from)
+					end = t.unsanitize(chunk, clean, end, start)
+					from = start
 				}
+				emit(p.text, offset+start, offset+end)
 			}
-			if uc == ' ' {
-				start = index + 1
-			} else {
-				start = index
+		})
+	})
+}
+
+// replace applies the tokenizer's replacements to chunk, as its sanitizer
+// does: at each offset, the first pair (in argument order) whose old string
+// matches is replaced. It also returns, for each byte offset in the result
+// (and its end), the corresponding offset in chunk; the bytes of a
+// replacement map to the start of the text that it replaced.
+func (t *iterTokenizer) replace(chunk string) (string, []int) {
+	var sb strings.Builder
+	offsets := make([]int, 0, len(chunk)+1)
+
+	for i := 0; i < len(chunk); {
+		replaced := false
+		for k := 0; k+1 < len(t.replacements); k += 2 {
+			old, repl := t.replacements[k], t.replacements[k+1]
+			if old != "" && strings.HasPrefix(chunk[i:], old) {
+				sb.WriteString(repl)
+				for range repl {
+					offsets = append(offsets, i)
+				}
+				i += len(old)
+				replaced = true
+				break
 			}
-			white = !white
 		}
-		index += size
+		if !replaced {
+			sb.WriteByte(chunk[i])
+			offsets = append(offsets, i)
+			i++
+		}
 	}
 
-	if start < index {
-		tokens = append(tokens, t.doSplit(clean[start:index])...)
+	return sb.String(), append(offsets, len(chunk))
+}
+
+// unsanitize maps the byte offset i in clean, the sanitized form of chunk,
+// back to an offset in chunk that is no less than from.
+//
+// A valid offset k splits chunk such that sanitizing each half separately
+// reproduces the corresponding halves of clean.
+func (t *iterTokenizer) unsanitize(chunk, clean string, i, from int) int {
+	if i >= len(clean) {
+		return len(chunk)
+	}
+	fallback := len(chunk)
+	for k := from; k <= len(chunk); k++ {
+		head := t.sanitizer.Replace(chunk[:k])
+		if head == clean[:i] && t.sanitizer.Replace(chunk[k:]) == clean[i:] {
+			return k
+		} else if len(head) >= i && k < fallback {
+			fallback = k
+		}
 	}
+	return fallback
+}
 
-	return tokens
+// forEachField calls fn for each maximal run of non-whitespace characters in
+// s, along with the run's byte offset.
+func forEachField(s string, fn func(int, string)) {
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fn(start, s[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		fn(start, s[start:])
+	}
 }
 
 var internalRE = regexp.MustCompile(`^(?:[A-Za-z]\.){2,}$|^[A-Z][a-z]{1,2}\.$`)
-var sanitizer = strings.NewReplacer(
+var sanitizations = []string{
 	"\u201c", `"`,
 	"\u201d", `"`,
 	"\u2018", "'",
 	"\u2019", "'",
-	"&rsquo;", "'")
+	"&rsquo;", "'"}
+var sanitizer = strings.NewReplacer(sanitizations...)
 var contractions = []string{"'ll", "'s", "'re", "'m", "n't"}
 var suffixes = []string{",", ")", `"`, "]", "!", ";", ".", "?", ":", "'"}
 var prefixes = []string{"$", "(", `"`, "["}
@@ -2,9 +2,16 @@ package tokenize_test
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"unicode/utf8"
 
 	"github.com/jdkato/twine/internal"
 	"github.com/jdkato/twine/nlp/tokenize"
@@ -204,6 +211,557 @@ func TestTokenizationContractions(t *testing.T) {
 	checkTokens(t, tokens, expected, "TokenizationContraction(custom-missing)")
 }
 
+func checkSpans(t *testing.T, text string, tokens []*tokenize.Token, name string) {
+	last := 0
+	for _, tok := range tokens {
+		span := tok.Span
+		if span.Start < last || span.End < span.Start || span.End > len(text) {
+			t.Fatalf("%v: invalid span %v for %q", name, span, tok.Text)
+		}
+		if utf8.RuneCountInString(text[:span.Start]) != span.RuneStart ||
+			utf8.RuneCountInString(text[:span.End]) != span.RuneEnd {
+			t.Errorf("%v: bad rune offsets %v for %q", name, span, tok.Text)
+		}
+		last = span.Start
+	}
+}
+
+func TestTokenSpans(t *testing.T) {
+	input, _ := getWordData("treebank_words.json")
+	quotes := strings.NewReplacer("“", `"`, "”", `"`, "’", "'")
+	for _, s := range input {
+		tokens := tokenize.NewIterTokenizer().Tokens(s)
+		checkSpans(t, s, tokens, "TokenSpans()")
+		for _, tok := range tokens {
+			if quotes.Replace(s[tok.Span.Start:tok.Span.End]) != tok.Text {
+				t.Errorf("TokenSpans(): %q != %q", s[tok.Span.Start:tok.Span.End], tok.Text)
+			}
+		}
+	}
+}
+
+func TestTokenSpansSanitized(t *testing.T) {
+	text := "“Hello,” I’m — (über) tired&rsquo;s"
+	tokens := tokenize.NewIterTokenizer().Tokens(text)
+	checkSpans(t, text, tokens, "TokenSpansSanitized()")
+
+	observed := []string{}
+	for _, tok := range tokens {
+		observed = append(observed, tok.Text+"="+text[tok.Span.Start:tok.Span.End])
+	}
+	expected := []string{
+		`"=“`, "Hello=Hello", ",=,", `"=”`, "I=I", "'m=’m", "—=—", "(=(",
+		"über=über", ")=)", "tired=tired", "'s=&rsquo;s"}
+	checkCase(t, observed, expected, "TokenSpansSanitized()")
+
+	if tokens[8].Span.RuneStart != 16 || tokens[8].Span.RuneEnd != 20 {
+		t.Errorf("TokenSpansSanitized(): unexpected rune span %v", tokens[8].Span)
+	}
+}
+
+func TestTokenSpansLongChunk(t *testing.T) {
+	// A long chunk that the sanitizer changes, with tokens split from its
+	// end, mustn't take quadratic time to map back to the text.
+	text := "“" + strings.Repeat("ab", 100000) + ".”"
+	for name, tok := range map[string]tokenize.Tokenizer{
+		"default":      tokenize.NewIterTokenizer(),
+		"replacements": tokenize.NewIterTokenizer(tokenize.UsingReplacements("’", "'", "“", `"`, "”", `"`)),
+	} {
+		tokens := tok.Tokens(text)
+		checkSpans(t, text, tokens, "TokenSpansLongChunk("+name+")")
+
+		last := tokens[len(tokens)-1]
+		if last.Span.End != len(text) || text[last.Span.Start:] != "”" {
+			t.Errorf("TokenSpansLongChunk(%v): unexpected last span %v", name, last.Span)
+		}
+	}
+}
+
+func TestRegexpTokenSpans(t *testing.T) {
+	text := "Größe matters.\n\n  Then: a new paragraph."
+
+	word := tokenize.NewWordBoundaryTokenizer()
+	tokens := word.Tokens(text)
+	checkSpans(t, text, tokens, "RegexpTokenSpans(words)")
+	checkCase(t, word.Tokenize(text), []string{
+		"Größe", "matters", "Then", "a", "new", "paragraph"}, "RegexpTokenSpans(words)")
+	if tokens[1].Span.Start != 8 || tokens[1].Span.RuneStart != 6 {
+		t.Errorf("RegexpTokenSpans(words): unexpected span %v", tokens[1].Span)
+	}
+
+	blank := tokenize.NewBlanklineTokenizer()
+	tokens = blank.Tokens(text)
+	checkSpans(t, text, tokens, "RegexpTokenSpans(gaps)")
+	for _, tok := range tokens {
+		if text[tok.Span.Start:tok.Span.End] != tok.Text {
+			t.Errorf("RegexpTokenSpans(gaps): %q != %q", text[tok.Span.Start:tok.Span.End], tok.Text)
+		}
+	}
+	checkCase(t, blank.Tokenize(text), []string{
+		"Größe matters.", "Then: a new paragraph."}, "RegexpTokenSpans(gaps)")
+}
+
+func TestTokenizerInterface(t *testing.T) {
+	tokenizers := map[string]tokenize.Tokenizer{
+		"iter":      tokenize.NewIterTokenizer(),
+		"treebank":  tokenize.NewTreebankWordTokenizer(),
+		"wordpunct": tokenize.NewWordPunctTokenizer(),
+		"boundary":  tokenize.NewWordBoundaryTokenizer(),
+		"blankline": tokenize.NewBlanklineTokenizer(),
+	}
+
+	input, _ := getWordData("treebank_words.json")
+	for name, tok := range tokenizers {
+		for _, s := range input {
+			observed := []string{}
+			for _, token := range tok.Tokens(s) {
+				observed = append(observed, token.Text)
+			}
+			expected := tok.Tokenize(s)
+			if len(observed) != 0 || len(expected) != 0 {
+				checkCase(t, observed, expected, "TokenizerInterface("+name+")")
+			}
+		}
+	}
+}
+
+func TestTokenKinds(t *testing.T) {
+	tokens := tokenize.NewIterTokenizer().Tokens("It costs $20.50 (+ tax), ok?")
+	expected := []tokenize.Kind{
+		tokenize.Word, tokenize.Word, tokenize.Symbol, tokenize.Number,
+		tokenize.Punct, tokenize.Symbol, tokenize.Word, tokenize.Punct,
+		tokenize.Punct, tokenize.Word, tokenize.Punct}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("TokenKinds(): got %d tokens, expected %d", len(tokens), len(expected))
+	}
+	for i, tok := range tokens {
+		if tok.Kind != expected[i] {
+			t.Errorf("TokenKinds(): %q is %v, expected %v", tok.Text, tok.Kind, expected[i])
+		}
+	}
+}
+
+func TestTokenEntities(t *testing.T) {
+	text := `Email jane.doe@example.com (or see https://example.com/docs?q=1.) ` +
+		`about src/main.go, ~/bin and C:\Temp\x.txt; upgrade to v2 or ` +
+		`1.4.2-rc1, follow @jdkato #golang :-) i.e. Mr. Smith and/or 3.14.`
+
+	observed := []string{}
+	for _, tok := range tokenize.NewIterTokenizer().Tokens(text) {
+		if tok.Kind != tokenize.Word && tok.Kind != tokenize.Punct {
+			observed = append(observed, tok.Kind.String()+":"+tok.Text)
+		}
+	}
+
+	expected := []string{
+		"Email:jane.doe@example.com", "URL:https://example.com/docs?q=1",
+		"Path:src/main.go", "Path:~/bin", `Path:C:\Temp\x.txt`, "Version:v2",
+		"Version:1.4.2-rc1", "Mention:@jdkato", "Hashtag:#golang",
+		"Emoticon::-)", "Abbreviation:i.e.", "Abbreviation:Mr.", "Number:3.14"}
+	checkCase(t, observed, expected, "TokenEntities()")
+}
+
+// scanTokens collects the tokens produced by s.
+func scanTokens(t *testing.T, s *tokenize.Scanner) []tokenize.Token {
+	observed := []tokenize.Token{}
+	for s.Scan() {
+		observed = append(observed, *s.Token())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner(): unexpected error: %v", err)
+	}
+	return observed
+}
+
+func derefTokens(tokens []*tokenize.Token) []tokenize.Token {
+	expected := []tokenize.Token{}
+	for _, tok := range tokens {
+		expected = append(expected, *tok)
+	}
+	return expected
+}
+
+func TestScanner(t *testing.T) {
+	tokenizers := map[string]interface {
+		tokenize.Tokenizer
+		Scanner(io.Reader) *tokenize.Scanner
+	}{
+		"iter":      tokenize.NewIterTokenizer(),
+		"wordpunct": tokenize.NewWordPunctTokenizer(),
+		"boundary":  tokenize.NewWordBoundaryTokenizer(),
+		"blankline": tokenize.NewBlanklineTokenizer(),
+	}
+
+	text := string(internal.ReadDataFile(filepath.Join(testdata, "article.txt")))
+	for name, tok := range tokenizers {
+		expected := derefTokens(tok.Tokens(text))
+
+		s := tok.Scanner(strings.NewReader(text))
+		checkTokenSlice(t, scanTokens(t, s), expected, "Scanner("+name+")")
+
+		// Force tokens to straddle both reads and buffer boundaries.
+		s = tok.Scanner(iotest.OneByteReader(strings.NewReader(text)))
+		s.Buffer(make([]byte, 1), tokenize.MaxScanTokenSize)
+		checkTokenSlice(t, scanTokens(t, s), expected, "Scanner("+name+", one byte)")
+	}
+}
+
+func TestScannerMultibyte(t *testing.T) {
+	text := "“Größe,” she said — 東京 über alles…\n\n  naïve café’s ÆON."
+	tokenizers := map[string]interface {
+		tokenize.Tokenizer
+		Scanner(io.Reader) *tokenize.Scanner
+	}{
+		"iter":      tokenize.NewIterTokenizer(),
+		"boundary":  tokenize.NewWordBoundaryTokenizer(),
+		"blankline": tokenize.NewBlanklineTokenizer(),
+	}
+
+	for name, tok := range tokenizers {
+		expected := derefTokens(tok.Tokens(text))
+		for size := 1; size <= 8; size++ {
+			s := tok.Scanner(iotest.HalfReader(strings.NewReader(text)))
+			s.Buffer(make([]byte, size), tokenize.MaxScanTokenSize)
+			observed := scanTokens(t, s)
+			checkTokenSlice(t, observed, expected, "ScannerMultibyte("+name+")")
+			for _, tok := range observed {
+				if text[tok.Span.Start:tok.Span.End] != tok.Text && name != "iter" {
+					t.Errorf("ScannerMultibyte(%v): %q != %q", name, text[tok.Span.Start:tok.Span.End], tok.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestScannerTooLong(t *testing.T) {
+	s := tokenize.NewIterTokenizer().Scanner(strings.NewReader("a supercalifragilistic word"))
+	s.Buffer(make([]byte, 2), 8)
+
+	observed := []string{}
+	for s.Scan() {
+		observed = append(observed, s.Token().Text)
+	}
+	checkCase(t, observed, []string{"a"}, "ScannerTooLong()")
+	if s.Err() != tokenize.ErrTokenTooLong {
+		t.Errorf("ScannerTooLong(): got %v, expected ErrTokenTooLong", s.Err())
+	}
+}
+
+func TestScannerNoMatch(t *testing.T) {
+	// Without gaps, a run of text that doesn't match can be longer than the
+	// buffer, since it isn't part of any token -- including when the buffer
+	// fills up in the middle of a match.
+	tok := tokenize.NewRegexpTokenizer(`#a*|[a-z]+`, false, false)
+	for name, text := range map[string]string{
+		"none": "start " + strings.Repeat("~ ", tokenize.MaxScanTokenSize) + "#aa end",
+		"pending": "start " + strings.Repeat("~", 10000) + "#" +
+			strings.Repeat("a", 60000) + " end",
+	} {
+		expected := derefTokens(tok.Tokens(text))
+		if len(expected) != 3 {
+			t.Fatalf("ScannerNoMatch(%v): unexpected tokens %v", name, expected)
+		}
+
+		s := tok.Scanner(iotest.HalfReader(strings.NewReader(text)))
+		checkTokenSlice(t, scanTokens(t, s), expected, "ScannerNoMatch("+name+")")
+	}
+
+	// With gaps, the text between two matches is a token.
+	text := strings.Repeat("~", tokenize.MaxScanTokenSize+1)
+	s := tokenize.NewRegexpTokenizer(`#`, true, false).Scanner(strings.NewReader(text))
+	for s.Scan() {
+	}
+	if s.Err() != tokenize.ErrTokenTooLong {
+		t.Errorf("ScannerNoMatch(gaps): got %v, expected ErrTokenTooLong", s.Err())
+	}
+}
+
+func checkTokenSlice(t *testing.T, observed, expected []tokenize.Token, name string) {
+	if !reflect.DeepEqual(observed, expected) {
+		t.Errorf("%v: got %d tokens, expected %d", name, len(observed), len(expected))
+		for i := range observed {
+			if i < len(expected) && observed[i] != expected[i] {
+				t.Errorf("%v: first difference: %v != %v", name, observed[i], expected[i])
+				break
+			}
+		}
+	}
+}
+
+func TestUnicodeWordBreaks(t *testing.T) {
+	// WordBreakTest.txt is the Unicode Consortium's conformance test for
+	// UAX #29: each line lists code points separated by "÷" (a boundary) or
+	// "×" (no boundary).
+	data := internal.ReadDataFile(filepath.Join(testdata, "WordBreakTest.txt"))
+
+	word := tokenize.NewUnicodeWordTokenizer()
+	for n, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var text strings.Builder
+		expected := []string{}
+		segment := ""
+		for _, field := range strings.Fields(line) {
+			switch field {
+			case "÷":
+				if segment != "" {
+					expected = append(expected, segment)
+				}
+				segment = ""
+			case "×":
+			default:
+				code, err := strconv.ParseUint(field, 16, 32)
+				if err != nil {
+					t.Fatalf("WordBreakTest.txt:%d: %v", n+1, err)
+				}
+				segment += string(rune(code))
+				text.WriteRune(rune(code))
+			}
+		}
+
+		observed := []string{}
+		for _, tok := range word.Segments(text.String()) {
+			observed = append(observed, tok.Text)
+		}
+		if !reflect.DeepEqual(observed, expected) {
+			t.Errorf("WordBreakTest.txt:%d: got %q, expected %q", n+1, observed, expected)
+		}
+	}
+}
+
+func TestUnicodeWordTokenizer(t *testing.T) {
+	text := "Ünïcode (naïve) café, $3.14 & 1,000 e-mails — 👩‍💻 🇺🇸 can't 東京"
+	word := tokenize.NewUnicodeWordTokenizer()
+
+	tokens := word.Tokens(text)
+	checkSpans(t, text, tokens, "UnicodeWordTokenizer()")
+	checkCase(t, word.Tokenize(text), []string{
+		"Ünïcode", "naïve", "café", "3.14", "1,000", "e", "mails", "👩‍💻", "🇺🇸",
+		"can't", "東", "京"}, "UnicodeWordTokenizer()")
+
+	// Combining marks stay with the letters they modify.
+	decomposed := "nai\u0308ve cafe\u0301"
+	checkCase(t, word.Tokenize(decomposed), []string{
+		"nai\u0308ve", "cafe\u0301"}, "UnicodeWordTokenizer(decomposed)")
+
+	segments := []string{}
+	for _, tok := range word.Segments("Hi, you!") {
+		segments = append(segments, tok.Text)
+	}
+	checkCase(t, segments, []string{"Hi", ",", " ", "you", "!"}, "UnicodeWordTokenizer(segments)")
+}
+
+func TestLoadProfile(t *testing.T) {
+	p, err := tokenize.LoadProfile(filepath.Join(testdata, "profile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := p.NewTokenizer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkCase(t, tok.Tokenize("We’ve moved C++ (and Node.js) to v1.2.3 :)"), []string{
+		"We", "'ve", "moved", "C++", "(", "and", "Node.js", ")", "to", "v1.2.3", ":", ")"},
+		"LoadProfile(profile.yml)")
+}
+
+func TestProfileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	text := "I've said it :) <3 -- we'd go (soon)."
+
+	for _, name := range tokenize.BuiltinProfiles() {
+		p, err := tokenize.BuiltinProfile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := p.NewTokenizer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ext := range []string{".json", ".yaml"} {
+			path := filepath.Join(dir, name+ext)
+			if err = tokenize.SaveProfile(path, p); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := tokenize.LoadProfile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded, p) {
+				t.Errorf("LoadProfile(%s): got %+v, expected %+v", path, loaded, p)
+			}
+			observed, err := loaded.NewTokenizer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCase(t, observed.Tokenize(text), expected.Tokenize(text), "LoadProfile("+path+")")
+		}
+	}
+}
+
+func TestBuiltinProfiles(t *testing.T) {
+	cases := []struct {
+		profile  string
+		text     string
+		expected []string
+	}{
+		{tokenize.EnglishProfile, "They'll see Mr. Smith's car.",
+			[]string{"They", "'ll", "see", "Mr.", "Smith", "'s", "car", "."}},
+		{tokenize.SocialProfile, "I've missed you :) <3",
+			[]string{"I", "'ve", "missed", "you", ":)", "<3"}},
+		{tokenize.TechnicalProfile, "Run `go test --count=1` or call os.Exit().",
+			[]string{"Run", "`", "go", "test", "--count=1", "`", "or", "call", "os.Exit()", "."}},
+	}
+
+	for _, c := range cases {
+		p, err := tokenize.BuiltinProfile(c.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tok, err := p.NewTokenizer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCase(t, tok.Tokenize(c.text), c.expected, "BuiltinProfile("+c.profile+")")
+	}
+
+	// The English profile is the default configuration.
+	p, _ := tokenize.BuiltinProfile(tokenize.EnglishProfile)
+	english, _ := p.NewTokenizer()
+	text := "\u201cHello,\u201d she said; it\u2019s (probably) fine :-)"
+	checkCase(t, english.Tokenize(text), tokenize.NewIterTokenizer().Tokenize(text), "BuiltinProfile(english)")
+
+	if _, err := tokenize.BuiltinProfile("legal"); err == nil {
+		t.Error("BuiltinProfile(legal): expected an error")
+	}
+}
+
+func TestProfileValidation(t *testing.T) {
+	data := []byte(`{
+  "name": "broken",
+  "suffixes": [".", "..."],
+  "emoticons": [""],
+  "special_pattern": "([a-z]",
+  "replacements": [{"from": "", "to": "x"}]
+}`)
+
+	_, err := tokenize.ParseProfile(data, tokenize.JSON)
+	if err == nil {
+		t.Fatal("ParseProfile(): expected an error")
+	}
+
+	fields := []string{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var profileErr *tokenize.ProfileError
+		if !errors.As(e, &profileErr) {
+			t.Fatalf("ParseProfile(): unexpected error %v", e)
+		}
+		fields = append(fields, profileErr.Field)
+	}
+	checkCase(t, fields, []string{
+		"emoticons[0]", "suffixes[1]", "special_pattern", "replacements[0].from"},
+		"ParseProfile(invalid)")
+
+	_, err = tokenize.ParseProfile([]byte("sufixes: [\".\"]\n"), tokenize.YAML)
+	if err == nil {
+		t.Error("ParseProfile(): expected an error for an unknown field")
+	}
+
+	if err = tokenize.SaveProfile(filepath.Join(t.TempDir(), "profile.toml"), &tokenize.Profile{}); err == nil {
+		t.Error("SaveProfile(): expected an error for an unknown format")
+	}
+}
+
+func TestMarkdownTokenizer(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testdata, "markup.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(b)
+
+	tokens := tokenize.NewMarkdownTokenizer().Tokens(text)
+	checkSpans(t, text, tokens, "MarkdownTokenizer()")
+
+	observed := []string{}
+	for _, tok := range tokens {
+		if text[tok.Span.Start:tok.Span.End] != tok.Text {
+			t.Errorf("MarkdownTokenizer(): %q != %q", text[tok.Span.Start:tok.Span.End], tok.Text)
+		}
+		if tok.Kind == tokenize.Code || tok.Kind == tokenize.URL || tok.Kind == tokenize.Email {
+			observed = append(observed, tok.Kind.String()+":"+tok.Text)
+		} else {
+			observed = append(observed, tok.Text)
+		}
+	}
+	checkCase(t, observed, []string{
+		"Getting", "started", "with", "twine",
+		"Install", "it", "with", "Code:go get github.com/jdkato/twine", ",", "then",
+		"read", "the", "tokenize", "docs",
+		"URL:https://pkg.go.dev/github.com/jdkato/twine/nlp/tokenize", "or", "the",
+		"FAQ", ".", "Questions", "?", "Email", "Email:help@example.com", ".",
+		"Note", ":", "the", "tokenizers", "do", "n't", "split", "Code:snake_case_names", ".",
+		"Code:tok := tokenize.NewIterTokenizer()\nfmt.Println(tok.Tokenize(\"Hello, world!\"))",
+		"Split", "prose", "into", "words",
+		"Keep", "URLs", "intact", ":", "URL:https://example.com/a_b",
+		"See", "the", "logo", "URL:img/logo.png", "below", ".",
+		"Option", "Meaning", "Code:-v", "Verbose",
+		"Code:indented code block",
+		"Some", "inline", "HTML", ",", "a", "URL:https://golang.org", "link", "and",
+		"a", "footnote", ".",
+		"Code:go test ./...",
+		"URL:https://example.com/faq",
+		"The", "footnote", "'s", "text", "."}, "MarkdownTokenizer()")
+
+	skipping := tokenize.NewMarkdownTokenizer(tokenize.SkippingCode(), tokenize.SkippingURLs())
+	for _, tok := range skipping.Tokens(text) {
+		if tok.Kind == tokenize.Code || tok.Kind == tokenize.URL {
+			t.Errorf("MarkdownTokenizer(skipping): unexpected token %q", tok.Text)
+		}
+	}
+}
+
+func TestMarkdownTokenizerInline(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{"Use `` a`b `` here", []string{"Use", "a`b", "here"}},
+		{"2 \\* 3 and 4 * 5", []string{"2", "*", "3", "and", "4", "*", "5"}},
+		{"[Nested `code]`](a(b)c) link", []string{"Nested", "code]", "a(b)c", "link"}},
+		{"Not a [link] (really)", []string{"Not", "a", "[", "link", "]", "(", "really", ")"}},
+		{"café *naïve* **résumé**", []string{"café", "naïve", "résumé"}},
+	}
+
+	tok := tokenize.NewMarkdownTokenizer()
+	for _, c := range cases {
+		tokens := tok.Tokens(c.text)
+		checkSpans(t, c.text, tokens, "MarkdownTokenizer("+c.text+")")
+		checkCase(t, tok.Tokenize(c.text), c.expected, "MarkdownTokenizer("+c.text+")")
+	}
+}
+
+func TestHTMLTokenizer(t *testing.T) {
+	text := `<p class="intro">Read the <a href='/docs/'>docs</a>, or run
+<code>go&nbsp;doc</code>.</p><script>var x = "<b>";</script><!-- done -->`
+
+	tok := tokenize.NewHTMLTokenizer(tokenize.UsingProseTokenizer(tokenize.NewTreebankWordTokenizer()))
+	tokens := tok.Tokens(text)
+	checkSpans(t, text, tokens, "HTMLTokenizer()")
+	checkCase(t, tok.Tokenize(text), []string{
+		"Read", "the", "/docs/", "docs", ",", "or", "run", "go&nbsp;doc", "."}, "HTMLTokenizer()")
+	if tokens[2].Kind != tokenize.URL || tokens[7].Kind != tokenize.Code {
+		t.Errorf("HTMLTokenizer(): unexpected kinds %v and %v", tokens[2].Kind, tokens[7].Kind)
+	}
+}
+
 func BenchmarkTokenization(b *testing.B) {
 	in := internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))
 	text := string(in)
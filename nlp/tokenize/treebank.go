@@ -1,14 +1,23 @@
 package tokenize
 
 import (
-	"regexp"
+	"bytes"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // TreebankWordTokenizer splits a sentence into words.
 //
-// This implementation is a port of the Sed script written by Robert McIntyre,
-// which is available at https://gist.github.com/jdkato/fc8b8c4266dba22d45ac85042ae53b1e.
+// This implementation is a port of NLTK's TreebankWordTokenizer (as of NLTK
+// 3.8.1), which is itself based on the Sed script written by Robert McIntyre
+// (available at https://gist.github.com/jdkato/fc8b8c4266dba22d45ac85042ae53b1e).
+//
+// Rather than using regular expressions, each of NLTK's rules is implemented
+// as a left-to-right scan over the text, and the rules are always applied in
+// NLTK's documented order. That's still one linear pass per rule (about 25 in
+// all) rather than a single pass, since a rule may match the output of an
+// earlier one.
 type TreebankWordTokenizer struct {
 }
 
@@ -17,50 +26,48 @@ func NewTreebankWordTokenizer() *TreebankWordTokenizer {
 	return new(TreebankWordTokenizer)
 }
 
-var startingQuotes = map[string]*regexp.Regexp{
-	"$1 `` ": regexp.MustCompile(`'([ (\[{<])"`),
-	"``":     regexp.MustCompile(`^(")`),
-	" ``":    regexp.MustCompile(`( ")`),
-}
-var startingQuotes2 = map[string]*regexp.Regexp{
-	" $1 ": regexp.MustCompile("(``)"),
-}
-var punctuation = map[string]*regexp.Regexp{
-	" $1 $2":   regexp.MustCompile(`([:,])([^\d])`),
-	" ... ":    regexp.MustCompile(`\.\.\.`),
-	"$1 $2$3 ": regexp.MustCompile(`([^\.])(\.)([\]\)}>"\']*)\s*$`),
-	"$1 ' ":    regexp.MustCompile(`([^'])' `),
-}
-var punctuation2 = []*regexp.Regexp{
-	regexp.MustCompile(`([:,])$`),
-	regexp.MustCompile(`([;@#$%&?!])`),
-}
-var brackets = map[string]*regexp.Regexp{
-	" $1 ": regexp.MustCompile(`([\]\[\(\)\{\}\<\>])`),
-	" -- ": regexp.MustCompile(`--`),
-}
-var endingQuotes = map[string]*regexp.Regexp{
-	" '' ": regexp.MustCompile(`"`),
-}
-var endingQuotes2 = []*regexp.Regexp{
-	regexp.MustCompile(`'(\S)(\'\')'`),
-	regexp.MustCompile(`([^' ])('[sS]|'[mM]|'[dD]|') `),
-	regexp.MustCompile(`([^' ])('ll|'LL|'re|'RE|'ve|'VE|n't|N'T) `),
-}
-var treebankContractions = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\b(can)(not)\b`),
-	regexp.MustCompile(`(?i)\b(d)('ye)\b`),
-	regexp.MustCompile(`(?i)\b(gim)(me)\b`),
-	regexp.MustCompile(`(?i)\b(gon)(na)\b`),
-	regexp.MustCompile(`(?i)\b(got)(ta)\b`),
-	regexp.MustCompile(`(?i)\b(lem)(me)\b`),
-	regexp.MustCompile(`(?i)\b(mor)('n)\b`),
-	regexp.MustCompile(`(?i)\b(wan)(na) `),
-	regexp.MustCompile(`(?i) ('t)(is)\b`),
-	regexp.MustCompile(`(?i) ('t)(was)\b`),
+// A treebankRule rewrites src, appending the result to dst.
+type treebankRule func(dst, src []byte) []byte
+
+// treebankRules are the Treebank rules, in the order in which NLTK applies
+// them.
+var treebankRules = []treebankRule{
+	// Starting quotes.
+	openingQuoteAtStart, // ^" -> ``
+	padString("``"),     // `` -> " `` "
+	openingQuotes,       // ([ (\[{<])("|'') -> $1 ``
+
+	// Punctuation.
+	padColonsAndCommas,         // ([:,])([^\d]) -> " $1 $2"
+	padFinalColonOrComma,       // ([:,])$ -> " $1 "
+	padString("..."),           // \.\.\. -> " ... "
+	padBytes(";@#$%&"),         // [;@#$%&] -> " $0 "
+	padFinalPeriod,             // ([^\.])(\.)([\]\)}>"\']*)\s*$ -> "$1 $2$3 "
+	padBytes("?!"),             // [?!] -> " $0 "
+	padClosingSingleQuotes,     // ([^'])' -> "$1 ' "
+	padBytes("[](){}<>"),       // [\]\[\(\)\{\}\<\>] -> " $0 "
+	padString("--"),            // -- -> " -- "
+	surroundWithSpaces,         // " " + text + " "
+	padString("''"),            // '' -> " '' "
+	closingQuotes,              // " -> " '' "
+	splitClitics(shortClitics), // ([^' ])('[sS]|'[mM]|'[dD]|') -> "$1 $2 "
+	splitClitics(longClitics),  // ([^' ])('ll|'LL|...|n't|N'T) -> "$1 $2 "
+
+	// Contractions.
+	splitContraction("can", "not", boundaryAfter),
+	splitContraction("d", "'ye", boundaryAfter),
+	splitContraction("gim", "me", boundaryAfter),
+	splitContraction("gon", "na", boundaryAfter),
+	splitContraction("got", "ta", boundaryAfter),
+	splitContraction("lem", "me", boundaryAfter),
+	splitContraction("mor", "'n", boundaryAfter),
+	splitContraction("wan", "na", spaceAfter),
+	splitTwas("'t", "is"),
+	splitTwas("'t", "was"),
 }
-var newlines = regexp.MustCompile(`(?:\n|\n\r|\r)`)
-var spaces = regexp.MustCompile(`(?: {2,})`)
+
+var shortClitics = []string{"'s", "'S", "'m", "'M", "'d", "'D", "'"}
+var longClitics = []string{"'ll", "'LL", "'re", "'RE", "'ve", "'VE", "n't", "N'T"}
 
 // Tokenize splits a sentence into a slice of words.
 //
@@ -72,41 +79,288 @@ var spaces = regexp.MustCompile(`(?: {2,})`)
 // NOTE: As mentioned above, this function expects a sentence (not raw text) as
 // input.
 func (t TreebankWordTokenizer) Tokenize(text string) []string {
-	for substitution, r := range startingQuotes {
-		text = r.ReplaceAllString(text, substitution)
+	src := []byte(text)
+	dst := make([]byte, 0, 2*len(src))
+	for _, rule := range treebankRules {
+		dst = rule(dst[:0], src)
+		src, dst = dst, src
+	}
+	return strings.Fields(string(src))
+}
+
+// Tokens splits a sentence into a slice of Tokens.
+//
+// The Treebank conventions rewrite some tokens (e.g., double quotes become
+// opening or closing Treebank-style quotes), but each Token's Span still
+// indexes into text itself.
+func (t TreebankWordTokenizer) Tokens(text string) []*Token {
+	return locate(text, t.Tokenize(text))
+}
+
+func openingQuoteAtStart(dst, src []byte) []byte {
+	if len(src) > 0 && src[0] == '"' {
+		return append(append(dst, "``"...), src[1:]...)
+	}
+	return append(dst, src...)
+}
+
+func openingQuotes(dst, src []byte) []byte {
+	for i := 0; i < len(src); i++ {
+		if strings.IndexByte(" ([{<", src[i]) < 0 {
+			dst = append(dst, src[i])
+		} else if bytes.HasPrefix(src[i+1:], []byte(`"`)) {
+			dst = append(append(dst, src[i]), " `` "...)
+			i++
+		} else if bytes.HasPrefix(src[i+1:], []byte("''")) {
+			dst = append(append(dst, src[i]), " `` "...)
+			i += 2
+		} else {
+			dst = append(dst, src[i])
+		}
+	}
+	return dst
+}
+
+func padColonsAndCommas(dst, src []byte) []byte {
+	for i := 0; i < len(src); i++ {
+		if src[i] != ':' && src[i] != ',' || i+1 == len(src) {
+			dst = append(dst, src[i])
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i+1:])
+		if unicode.IsDigit(r) {
+			dst = append(dst, src[i])
+			continue
+		}
+		// The following character is consumed by the match, so it can't
+		// start another one.
+		dst = append(append(dst, ' ', src[i], ' '), src[i+1:i+1+size]...)
+		i += size
+	}
+	return dst
+}
+
+func padFinalColonOrComma(dst, src []byte) []byte {
+	n := len(src)
+	if n > 0 && (src[n-1] == ':' || src[n-1] == ',') {
+		return append(append(dst, src[:n-1]...), ' ', src[n-1], ' ')
+	}
+	return append(dst, src...)
+}
+
+func padFinalPeriod(dst, src []byte) []byte {
+	end := len(bytes.TrimRightFunc(src, unicode.IsSpace))
+	closers := len(bytes.TrimRight(src[:end], `])}>"'`))
+	if closers == 0 || src[closers-1] != '.' || closers < 2 || src[closers-2] == '.' {
+		return append(dst, src...)
 	}
+	dst = append(append(dst, src[:closers-1]...), " ."...)
+	return append(append(dst, src[closers:end]...), ' ')
+}
 
-	for substitution, r := range startingQuotes2 {
-		text = r.ReplaceAllString(text, substitution)
+func padClosingSingleQuotes(dst, src []byte) []byte {
+	for i := 0; i < len(src); {
+		_, size := utf8.DecodeRune(src[i:])
+		if src[i] != '\'' && bytes.HasPrefix(src[i+size:], []byte("' ")) {
+			dst = append(append(dst, src[i:i+size]...), " ' "...)
+			i += size + 2
+		} else {
+			dst = append(dst, src[i:i+size]...)
+			i += size
+		}
 	}
+	return dst
+}
+
+func surroundWithSpaces(dst, src []byte) []byte {
+	return append(append(append(dst, ' '), src...), ' ')
+}
 
-	for substitution, r := range punctuation {
-		text = r.ReplaceAllString(text, substitution)
+func closingQuotes(dst, src []byte) []byte {
+	for _, b := range src {
+		if b == '"' {
+			dst = append(dst, " '' "...)
+		} else {
+			dst = append(dst, b)
+		}
 	}
+	return dst
+}
 
-	for _, r := range punctuation2 {
-		text = r.ReplaceAllString(text, " $1 ")
+// padString returns a rule that surrounds every non-overlapping occurrence of
+// s with spaces.
+func padString(s string) treebankRule {
+	return func(dst, src []byte) []byte {
+		for {
+			i := bytes.Index(src, []byte(s))
+			if i < 0 {
+				return append(dst, src...)
+			}
+			dst = append(append(append(append(dst, src[:i]...), ' '), s...), ' ')
+			src = src[i+len(s):]
+		}
 	}
+}
 
-	for substitution, r := range brackets {
-		text = r.ReplaceAllString(text, substitution)
+// padBytes returns a rule that surrounds every occurrence of the bytes in set
+// with spaces.
+func padBytes(set string) treebankRule {
+	var table [256]bool
+	for i := 0; i < len(set); i++ {
+		table[set[i]] = true
+	}
+	return func(dst, src []byte) []byte {
+		for _, b := range src {
+			if table[b] {
+				dst = append(dst, ' ', b, ' ')
+			} else {
+				dst = append(dst, b)
+			}
+		}
+		return dst
 	}
+}
 
-	text = " " + text + " "
+// splitClitics returns a rule that separates any of the given clitics from
+// the preceding character (which can't be a space or single quote), as long
+// as the clitic is followed by a space.
+func splitClitics(clitics []string) treebankRule {
+	return func(dst, src []byte) []byte {
+		last := 0
+		for i := 1; i < len(src); i++ {
+			if src[i] != '\'' && src[i] != 'n' && src[i] != 'N' {
+				continue
+			}
+			clitic := cliticPrefix(src[i:], clitics)
+			if clitic == "" {
+				continue
+			}
+			prev, size := utf8.DecodeLastRune(src[:i])
+			if i-size < last || prev == '\'' || prev == ' ' {
+				continue
+			}
+			dst = append(append(dst, src[last:i]...), ' ')
+			dst = append(append(dst, clitic...), ' ')
+			last = i + len(clitic) + 1
+			i = last - 1
+		}
+		return append(dst, src[last:]...)
+	}
+}
 
-	for substitution, r := range endingQuotes {
-		text = r.ReplaceAllString(text, substitution)
+// cliticPrefix returns the first of the clitics that, followed by a space,
+// is a prefix of b.
+func cliticPrefix(b []byte, clitics []string) string {
+	for _, clitic := range clitics {
+		if len(b) > len(clitic) && string(b[:len(clitic)]) == clitic && b[len(clitic)] == ' ' {
+			return clitic
+		}
 	}
+	return ""
+}
+
+// A contractionEnd determines if a contraction ends at the start of b and, if
+// so, how many bytes of b belong to the match.
+type contractionEnd func(b []byte) (bool, int)
+
+// boundaryAfter requires a word boundary after the contraction.
+func boundaryAfter(b []byte) (bool, int) {
+	r, _ := utf8.DecodeRune(b)
+	return len(b) == 0 || !isWordRune(r), 0
+}
 
-	for _, r := range endingQuotes2 {
-		text = r.ReplaceAllString(text, "$1 $2 ")
+// spaceAfter requires (and consumes) a whitespace character after the
+// contraction.
+func spaceAfter(b []byte) (bool, int) {
+	r, size := utf8.DecodeRune(b)
+	return len(b) > 0 && unicode.IsSpace(r), size
+}
+
+// splitContraction returns a rule that splits, case-insensitively, the word
+// first+second into " first second " (e.g., "cannot" -> " can not ").
+func splitContraction(first, second string, end contractionEnd) treebankRule {
+	word := first + second
+	return func(dst, src []byte) []byte {
+		last := 0
+		for i := 0; i < len(src); i++ {
+			if lowerASCII(src[i]) != word[0] || !hasPrefixFold(src[i:], word) {
+				continue
+			}
+			if prev, _ := utf8.DecodeLastRune(src[:i]); i > 0 && isWordRune(prev) {
+				continue
+			}
+			ok, extra := end(src[i+len(word):])
+			if !ok {
+				continue
+			}
+			dst = append(append(dst, src[last:i]...), ' ')
+			dst = append(append(dst, src[i:i+len(first)]...), ' ')
+			dst = append(append(dst, src[i+len(first):i+len(word)]...), ' ')
+			last = i + len(word) + extra
+			i = last - 1
+		}
+		return append(dst, src[last:]...)
+	}
+}
+
+// splitTwas returns a rule that splits, case-insensitively, a space-preceded
+// first+second into " first second " (e.g., " 'twas" -> " 't was ").
+func splitTwas(first, second string) treebankRule {
+	word := " " + first + second
+	return func(dst, src []byte) []byte {
+		for {
+			i := indexFold(src, word)
+			if i < 0 {
+				return append(dst, src...)
+			}
+			rest := src[i+len(word):]
+			if ok, _ := boundaryAfter(rest); !ok {
+				dst = append(dst, src[:i+1]...)
+				src = src[i+1:]
+				continue
+			}
+			dst = append(append(dst, src[:i]...), ' ')
+			dst = append(append(dst, src[i+1:i+1+len(first)]...), ' ')
+			dst = append(append(dst, src[i+1+len(first):i+len(word)]...), ' ')
+			src = rest
+		}
+	}
+}
+
+// isWordRune determines if r is a "word" character (in the regular
+// expression sense).
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// hasPrefixFold determines if b starts with the ASCII string s, ignoring
+// case.
+func hasPrefixFold(b []byte, s string) bool {
+	if len(b) < len(s) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if lowerASCII(b[i]) != lowerASCII(s[i]) {
+			return false
+		}
 	}
+	return true
+}
 
-	for _, r := range treebankContractions {
-		text = r.ReplaceAllString(text, " $1 $2 ")
+// indexFold returns the index of the first occurrence of the ASCII string s
+// in b, ignoring case, or -1 if s isn't present.
+func indexFold(b []byte, s string) int {
+	for i := 0; i+len(s) <= len(b); i++ {
+		if hasPrefixFold(b[i:], s) {
+			return i
+		}
 	}
+	return -1
+}
 
-	text = newlines.ReplaceAllString(text, " ")
-	text = strings.TrimSpace(spaces.ReplaceAllString(text, " "))
-	return strings.Split(text, " ")
+func lowerASCII(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
 }
@@ -41,6 +41,68 @@ func TestTreebankWordTokenizer(t *testing.T) {
 	}
 }
 
+// treebank_words_nltk.json is generated by treebank_nltk.py.
+func TestTreebankWordTokenizerNLTK(t *testing.T) {
+	input, output := getWordData("treebank_words_nltk.json")
+	word := tokenize.NewTreebankWordTokenizer()
+	for i, s := range input {
+		if words := word.Tokenize(s); !reflect.DeepEqual(words, output[i]) {
+			t.Errorf("%q: got %q, want %q", s, words, output[i])
+		}
+	}
+}
+
+func TestTreebankTokenSpans(t *testing.T) {
+	input, _ := getOldWordData("treebank_words_old.json")
+	word := tokenize.NewTreebankWordTokenizer()
+	for _, s := range input {
+		tokens := word.Tokens(s)
+		checkSpans(t, s, tokens, "TreebankTokenSpans()")
+		for _, tok := range tokens {
+			source := s[tok.Span.Start:tok.Span.End]
+			if source != tok.Text && source != `"` && source != "''" {
+				t.Errorf("TreebankTokenSpans(): %q != %q", source, tok.Text)
+			}
+		}
+	}
+
+	text := `He said, "I cannot go."`
+	observed := []string{}
+	for _, tok := range word.Tokens(text) {
+		observed = append(observed, text[tok.Span.Start:tok.Span.End])
+	}
+	expected := []string{"He", "said", ",", `"`, "I", "can", "not", "go", ".", `"`}
+	checkCase(t, observed, expected, "TreebankTokenSpans()")
+
+	text = "He said ''hi''"
+	observed = []string{}
+	for _, tok := range word.Tokens(text) {
+		observed = append(observed, text[tok.Span.Start:tok.Span.End])
+	}
+	expected = []string{"He", "said", "''", "hi", "''"}
+	checkCase(t, observed, expected, "TreebankTokenSpans()")
+}
+
+func TestTreebankWordTokenizerRules(t *testing.T) {
+	word := tokenize.NewTreebankWordTokenizer()
+	cases := map[string][]string{
+		`"Cannot," she said ("gimme 'twas").`: {
+			"``", "Can", "not", ",", "''", "she", "said", "(", "``", "gim",
+			"me", "'t", "was", "''", ")", "."},
+		"The dogs' owners\tweren't there:": {
+			"The", "dogs", "'", "owners", "were", "n't", "there", ":"},
+		"It costs $1,000 -- or 1:30 o'clock...": {
+			"It", "costs", "$", "1,000", "--", "or", "1:30", "o'clock", "..."},
+		"He said ''hi'' to (''them'').": {
+			"He", "said", "``", "hi", "''", "to", "(", "``", "them", "''", ")", "."},
+		"''Hi,'' she said.": {"''", "Hi", ",", "''", "she", "said", "."},
+		"":                  {},
+	}
+	for input, expected := range cases {
+		checkCase(t, word.Tokenize(input), expected, "TreebankWordTokenizerRules()")
+	}
+}
+
 func BenchmarkTreebankWordTokenizer(b *testing.B) {
 	word := tokenize.NewTreebankWordTokenizer()
 	for n := 0; n < b.N; n++ {
@@ -49,3 +111,55 @@ func BenchmarkTreebankWordTokenizer(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkTreebankWordTokenizerLong(b *testing.B) {
+	in := internal.ReadDataFile(filepath.Join(testdata, "sherlock.txt"))
+	text := string(in)
+
+	word := tokenize.NewTreebankWordTokenizer()
+	for n := 0; n < b.N; n++ {
+		_ = word.Tokenize(text)
+	}
+}
+
+func TestTreebankWordDetokenizer(t *testing.T) {
+	cases := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"They", "'ll", "save", "and", "invest", "more", "."},
+			"They'll save and invest more."},
+		{[]string{"I", "ca", "n't", "go", ",", "I", "'m", "gon", "na", "stay", "."},
+			"I can't go, I'm gonna stay."},
+		{[]string{"He", "said", ",", "``", "I", "can", "not", "go", ".", "''"},
+			`He said, "I cannot go."`},
+		{[]string{"(", "See", "page", "5", ")", ";", "it", "costs", "$", "10", "--", "or", "20", "%", "less", "!"},
+			"(See page 5); it costs $10--or 20% less!"},
+		{[]string{"``", "Wait", "...", "what", "?", "''", "she", "asked", "."},
+			`"Wait...what?" she asked.`},
+		{[]string{"The", "students", "'", "books", "."},
+			"The students' books."},
+		{[]string{"'T", "is", "the", "season", "."},
+			"'Tis the season."},
+		{[]string{}, ""},
+	}
+
+	detok := tokenize.NewTreebankWordDetokenizer()
+	for _, c := range cases {
+		if got := detok.Detokenize(c.words); got != c.want {
+			t.Errorf("Detokenize(%q) = %q, want %q", c.words, got, c.want)
+		}
+	}
+}
+
+func TestTreebankWordDetokenizerRoundTrip(t *testing.T) {
+	input, output := getOldWordData("treebank_words_old.json")
+	word := tokenize.NewTreebankWordTokenizer()
+	detok := tokenize.NewTreebankWordDetokenizer()
+	for i, words := range output {
+		text := detok.Detokenize(words)
+		if !reflect.DeepEqual(word.Tokenize(text), words) {
+			t.Errorf("%q: round trip gave %q, want %q", input[i], word.Tokenize(text), words)
+		}
+	}
+}
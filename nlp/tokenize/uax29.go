@@ -0,0 +1,270 @@
+package tokenize
+
+import (
+	"sort"
+	"unicode"
+)
+
+//go:generate go run gen_wordbreak.go
+
+// UnicodeWordTokenizer splits text into words at the word boundaries defined
+// by the Unicode Text Segmentation algorithm (UAX #29).
+//
+// Unlike a regular expression, the algorithm keeps combining marks and
+// emoji sequences (e.g., "👩‍💻" or a flag) with the characters they modify,
+// keeps numbers such as "3.14" and "1,000" together, and splits scripts
+// written without spaces (e.g., Chinese or Thai) into individual characters.
+//
+// See https://www.unicode.org/reports/tr29/#Word_Boundaries.
+type UnicodeWordTokenizer struct {
+}
+
+// NewUnicodeWordTokenizer is a UnicodeWordTokenizer constructor.
+func NewUnicodeWordTokenizer() *UnicodeWordTokenizer {
+	return new(UnicodeWordTokenizer)
+}
+
+// Tokenize splits text into a slice of words.
+func (t UnicodeWordTokenizer) Tokenize(text string) []string {
+	return texts(t.Tokens(text))
+}
+
+// Tokens splits text into a slice of word Tokens.
+//
+// Only word-like segments -- those containing a letter, a number or an emoji
+// -- are returned; see Segments for every segment.
+func (t UnicodeWordTokenizer) Tokens(text string) []*Token {
+	var tokens []*Token
+
+	counter := runeCounter{text: text}
+	segmentWords(text, func(start, end int, word bool) {
+		if word {
+			tok := text[start:end]
+			tokens = append(tokens, &Token{
+				Text: tok, Span: counter.span(start, end), Kind: Classify(tok)})
+		}
+	})
+
+	return tokens
+}
+
+// Segments splits text into a slice of Tokens at every word boundary, so the
+// Tokens include whitespace and punctuation and, together, cover all of text.
+func (t UnicodeWordTokenizer) Segments(text string) []*Token {
+	var tokens []*Token
+
+	counter := runeCounter{text: text}
+	segmentWords(text, func(start, end int, _ bool) {
+		tok := text[start:end]
+		tokens = append(tokens, &Token{
+			Text: tok, Span: counter.span(start, end), Kind: Classify(tok)})
+	})
+
+	return tokens
+}
+
+// wordBreak is the value of a code point's Word_Break property.
+type wordBreak uint8
+
+const (
+	wbOther wordBreak = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend // Extend or Format
+	wbZWJ
+	wbRegionalIndicator
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+// A wordBreakRange assigns a property to the code points lo through hi.
+type wordBreakRange struct {
+	lo, hi rune
+	prop   wordBreak
+}
+
+func lookupRange(ranges []wordBreakRange, r rune) (wordBreak, bool) {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi >= r })
+	if i < len(ranges) && ranges[i].lo <= r {
+		return ranges[i].prop, true
+	}
+	return wbOther, false
+}
+
+// wbChar is a single code point of the text being segmented.
+type wbChar struct {
+	offset int
+	prop   wordBreak
+	pict   bool // Extended_Pictographic
+	word   bool // a letter, a number or an emoji
+}
+
+func newWBChar(offset int, r rune) wbChar {
+	prop, _ := lookupRange(wordBreakRanges, r)
+	_, pict := lookupRange(pictographicRanges, r)
+	word := pict || prop == wbRegionalIndicator || unicode.IsLetter(r) || unicode.IsNumber(r)
+	return wbChar{offset: offset, prop: prop, pict: pict, word: word}
+}
+
+func (c wbChar) is(props ...wordBreak) bool {
+	for _, p := range props {
+		if c.prop == p {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored reports whether c is absorbed by the preceding character (WB4).
+func (c wbChar) ignored() bool {
+	return c.is(wbExtend, wbZWJ)
+}
+
+func (c wbChar) newline() bool {
+	return c.is(wbCR, wbLF, wbNewline)
+}
+
+func (c wbChar) ahLetter() bool {
+	return c.is(wbALetter, wbHebrewLetter)
+}
+
+func (c wbChar) midNumLetQ() bool {
+	return c.is(wbMidNumLet, wbSingleQuote)
+}
+
+// segmentWords calls emit with the byte offsets of each segment of text
+// delimited by word boundaries, along with whether or not the segment is
+// word-like.
+func segmentWords(text string, emit func(int, int, bool)) {
+	chars := make([]wbChar, 0, len(text))
+	for i, r := range text {
+		chars = append(chars, newWBChar(i, r))
+	}
+
+	start, word := 0, false
+	for i, c := range chars {
+		if i > 0 && wordBoundary(chars, i) {
+			emit(start, c.offset, word)
+			start, word = c.offset, false
+		}
+		word = word || c.word
+	}
+
+	if len(chars) > 0 {
+		emit(start, len(text), word)
+	}
+}
+
+// wordBoundary reports whether there's a word boundary between chars[i-1]
+// and chars[i], applying the rules of UAX #29 in order.
+func wordBoundary(chars []wbChar, i int) bool {
+	prev, cur := chars[i-1], chars[i]
+
+	switch {
+	case prev.prop == wbCR && cur.prop == wbLF: // WB3
+		return false
+	case prev.newline() || cur.newline(): // WB3a, WB3b
+		return true
+	case prev.prop == wbZWJ && cur.pict: // WB3c
+		return false
+	case prev.prop == wbWSegSpace && cur.prop == wbWSegSpace: // WB3d
+		return false
+	case cur.ignored(): // WB4
+		return false
+	}
+
+	// The remaining rules treat a character and the Extend, Format and ZWJ
+	// characters that follow it as a single unit (WB4).
+	p := before(chars, i)
+	prev = chars[p]
+
+	var next, prevPrev wbChar
+	if n := after(chars, i); n < len(chars) {
+		next = chars[n]
+	}
+	if p > 0 {
+		prevPrev = chars[before(chars, p)]
+	}
+
+	switch {
+	case prev.ahLetter() && cur.ahLetter(): // WB5
+		return false
+	case prev.ahLetter() && (cur.prop == wbMidLetter || cur.midNumLetQ()) && next.ahLetter(): // WB6
+		return false
+	case prevPrev.ahLetter() && (prev.prop == wbMidLetter || prev.midNumLetQ()) && cur.ahLetter(): // WB7
+		return false
+	case prev.prop == wbHebrewLetter && cur.prop == wbSingleQuote: // WB7a
+		return false
+	case prev.prop == wbHebrewLetter && cur.prop == wbDoubleQuote && next.prop == wbHebrewLetter: // WB7b
+		return false
+	case prevPrev.prop == wbHebrewLetter && prev.prop == wbDoubleQuote && cur.prop == wbHebrewLetter: // WB7c
+		return false
+	case prev.prop == wbNumeric && cur.prop == wbNumeric: // WB8
+		return false
+	case prev.ahLetter() && cur.prop == wbNumeric: // WB9
+		return false
+	case prev.prop == wbNumeric && cur.ahLetter(): // WB10
+		return false
+	case prevPrev.prop == wbNumeric && (prev.prop == wbMidNum || prev.midNumLetQ()) && cur.prop == wbNumeric: // WB11
+		return false
+	case prev.prop == wbNumeric && (cur.prop == wbMidNum || cur.midNumLetQ()) && next.prop == wbNumeric: // WB12
+		return false
+	case prev.prop == wbKatakana && cur.prop == wbKatakana: // WB13
+		return false
+	case (prev.ahLetter() || prev.is(wbNumeric, wbKatakana, wbExtendNumLet)) && cur.prop == wbExtendNumLet: // WB13a
+		return false
+	case prev.prop == wbExtendNumLet && (cur.ahLetter() || cur.is(wbNumeric, wbKatakana)): // WB13b
+		return false
+	case prev.prop == wbRegionalIndicator && cur.prop == wbRegionalIndicator: // WB15, WB16
+		return regionalIndicators(chars, p)%2 == 0
+	}
+
+	return true // WB999
+}
+
+// before returns the index of the character that chars[i-1] belongs to once
+// any Extend, Format and ZWJ characters are attached to the character that
+// precedes them.
+func before(chars []wbChar, i int) int {
+	j := i - 1
+	for j > 0 && chars[j].ignored() && !chars[j-1].newline() {
+		j--
+	}
+	return j
+}
+
+// after returns the index of the first character after chars[i] that isn't
+// attached to it.
+func after(chars []wbChar, i int) int {
+	j := i + 1
+	for j < len(chars) && chars[j].ignored() {
+		j++
+	}
+	return j
+}
+
+// regionalIndicators counts the run of Regional_Indicator characters that
+// ends with chars[i].
+func regionalIndicators(chars []wbChar, i int) int {
+	count := 0
+	for {
+		if chars[i].prop != wbRegionalIndicator {
+			return count
+		}
+		count++
+		if i == 0 {
+			return count
+		}
+		i = before(chars, i)
+	}
+}
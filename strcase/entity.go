@@ -0,0 +1,59 @@
+package strcase
+
+import (
+	"strings"
+
+	"github.com/jdkato/twine/nlp/tokenize"
+)
+
+var entityTokenizer = tokenize.NewIterTokenizer()
+
+// verbatimKinds are the kinds of tokens whose case is never changed, since
+// doing so could alter their meaning (e.g., a case-sensitive URL).
+var verbatimKinds = map[tokenize.Kind]bool{
+	tokenize.URL:     true,
+	tokenize.Email:   true,
+	tokenize.Path:    true,
+	tokenize.Version: true,
+	tokenize.Hashtag: true,
+	tokenize.Mention: true,
+}
+
+// A verbatimFinder locates the portions of a string that should be left
+// as-is.
+type verbatimFinder struct {
+	text   string
+	spans  []tokenize.Span
+	cursor int
+}
+
+func newVerbatimFinder(s string) *verbatimFinder {
+	finder := &verbatimFinder{text: s}
+	for _, tok := range entityTokenizer.Tokens(s) {
+		if verbatimKinds[tok.Kind] {
+			finder.spans = append(finder.spans, tok.Span)
+		}
+	}
+	return finder
+}
+
+// next reports whether the next occurrence of m, a substring of the finder's
+// text, falls within a verbatim token.
+//
+// Substrings must be given in the order in which they occur.
+func (v *verbatimFinder) next(m string) bool {
+	at := strings.Index(v.text[v.cursor:], m)
+	if at < 0 {
+		return false
+	}
+
+	at += v.cursor
+	v.cursor = at + len(m)
+	for _, span := range v.spans {
+		if at >= span.Start && at < span.End {
+			return true
+		}
+	}
+
+	return false
+}
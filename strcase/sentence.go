@@ -62,6 +62,7 @@ func (sc *SentenceConverter) Convert(s string) string {
 	re := regexp2.MustCompileStd(`(?i)` + ps)
 
 	tokens := re.FindAllString(s, -1)
+	verbatim := newVerbatimFinder(s)
 	// NOTE: We have to do this *after* tokenizing the string in order to
 	// respect the case of would-be exceptions.
 	s = strings.ToLower(s)
@@ -72,7 +73,9 @@ func (sc *SentenceConverter) Convert(s string) string {
 			prev = tokens[i-1]
 		}
 
-		if entry := sc.inVocab(token); entry != "" {
+		if verbatim.next(token) {
+			made = append(made, token)
+		} else if entry := sc.inVocab(token); entry != "" {
 			made = append(made, entry)
 		} else if i == 0 || sc.indicator(prev, i-1) {
 			made = append(made, internal.ToTitle(token, true))
@@ -15,6 +15,8 @@ var cases = []testCase{
 	{"Intro to the top-level idEas", "Intro to the top-level ideas"},
 	{"build-backend tools", "Build-backend tools"},
 	{"README files", "Readme files"},
+	{"Email Jane.Doe@Example.com About v1.2", "Email Jane.Doe@Example.com about v1.2"},
+	{"https://Example.com/Docs Explained", "https://Example.com/Docs explained"},
 }
 
 var vocabCases = []testCase{
@@ -99,6 +99,7 @@ func (tc *TitleConverter) Convert(s string) string {
 	tags := tagger.Tag(words)
 	widx := -1
 
+	verbatim := newVerbatimFinder(s)
 	return prefix + splitRE.ReplaceAllStringFunc(s, func(m string) string {
 		widx += 1
 
@@ -108,7 +109,9 @@ func (tc *TitleConverter) Convert(s string) string {
 		ext := utf8.RuneCountInString(m)
 
 		idx = pos + ext
-		if found := tc.inVocab(m); found != "" {
+		if verbatim.next(m) {
+			return m
+		} else if found := tc.inVocab(m); found != "" {
 			return found
 		} else if tc.ignore(sm, tags, widx, pos == 0 || idx == end) &&
 			(prev == ' ' || prev == '-' || prev == '/') &&
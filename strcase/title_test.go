@@ -23,6 +23,8 @@ var vocabTitles = []testCase{
 	{"b. Next title text", "b. Next Title Text"},
 	{"vale ale", "Vale ale"},
 	{"New Repository and Project", "New Repository and Project"},
+	{"reading https://example.com/docs and README.md", "Reading https://example.com/docs and README.md"},
+	{"ask @jdkato about src/main.go", "Ask @jdkato About src/main.go"},
 }
 
 func TestTitleVocab(t *testing.T) {
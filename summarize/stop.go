@@ -1,5 +1,14 @@
 package summarize
 
+import "strings"
+
+// IsStopWord reports whether word, ignoring case, is one of the stop words
+// that Keywords omits.
+func IsStopWord(word string) bool {
+	_, found := stopWords[strings.ToLower(word)]
+	return found
+}
+
 var stopWords = map[string]struct{}{
 	"a":            empty,
 	"about":        empty,
@@ -10,6 +10,7 @@ import (
 
 	"github.com/jdkato/twine/internal"
 	"github.com/jdkato/twine/nlp/segment"
+	"github.com/jdkato/twine/nlp/stem"
 	"github.com/jdkato/twine/nlp/tokenize"
 
 	"github.com/montanaflynn/stats"
@@ -17,6 +18,14 @@ import (
 
 var sentenceTokenizer = segment.NewPunktSentenceTokenizer()
 var wordTokenizer = tokenize.NewWordBoundaryTokenizer()
+var entityTokenizer = tokenize.NewIterTokenizer()
+
+// ignoredKinds are the kinds of tokens that aren't counted as words, since
+// they'd otherwise be split into many meaningless "words" (e.g., "https").
+var ignoredKinds = map[tokenize.Kind]bool{
+	tokenize.URL:   true,
+	tokenize.Email: true,
+}
 
 // A Word represents a single word in a Document.
 type Word struct {
@@ -41,25 +50,34 @@ type RankedParagraph struct {
 
 // A Document represents a collection of text to be analyzed.
 //
-// A Document's calculations depend on its word and sentence tokenizers. You
-// can use the defaults by invoking NewDocument, choose another implemention
-// from the tokenize package, or use your own (as long as it implements the
-// ProseTokenizer interface). For example,
+// A Document's calculations depend on its word tokenizer. You can use the
+// default by invoking NewDocument, choose another implemention from the
+// tokenize package, or use your own (as long as it implements the
+// tokenize.Tokenizer interface). For example,
 //
-//	d := Document{Content: ..., WordTokenizer: ..., SentenceTokenizer: ...}
+//	d := Document{Content: ..., WordTokenizer: tokenize.NewUnicodeWordTokenizer()}
 //	d.Initialize()
+//
+// Similarly, setting a Stemmer (e.g., stem.NewEnglishStemmer()) groups the
+// inflections of a word together when computing its Keywords, and setting a
+// Segmenter (e.g., segment.NewMarkdownSegmenter() for Markdown) changes how
+// its Content is split into sentences. Without one, Content is split into
+// paragraphs at blank lines, each of which is split by a Punkt segmenter.
 type Document struct {
-	Content         string         // Actual text
-	NumCharacters   float64        // Number of Characters
-	NumComplexWords float64        // PolysylWords without common suffixes
-	NumParagraphs   float64        // Number of paragraphs
-	NumPolysylWords float64        // Number of words with > 2 syllables
-	NumSentences    float64        // Number of sentences
-	NumSyllables    float64        // Number of syllables
-	NumWords        float64        // Number of words
-	NumLongWords    float64        // Number of long words
-	Sentences       []Sentence     // the Document's sentences
-	WordFrequency   map[string]int // [word]frequency
+	Content         string             // Actual text
+	WordTokenizer   tokenize.Tokenizer // Splits sentences into words
+	Segmenter       segment.Segmenter  // Splits Content into sentences (optional)
+	Stemmer         stem.Stemmer       // Groups inflected words (optional)
+	NumCharacters   float64            // Number of Characters
+	NumComplexWords float64            // PolysylWords without common suffixes
+	NumParagraphs   float64            // Number of paragraphs
+	NumPolysylWords float64            // Number of words with > 2 syllables
+	NumSentences    float64            // Number of sentences
+	NumSyllables    float64            // Number of syllables
+	NumWords        float64            // Number of words
+	NumLongWords    float64            // Number of long words
+	Sentences       []Sentence         // the Document's sentences
+	WordFrequency   map[string]int     // [word]frequency
 }
 
 // An Assessment provides comprehensive access to a Document's metrics.
@@ -98,44 +116,72 @@ func NewDocument(text string) *Document {
 // statistics.
 func (d *Document) Initialize() {
 	d.WordFrequency = make(map[string]int)
-	for i, paragraph := range strings.Split(d.Content, "\n\n") {
-		for _, s := range sentenceTokenizer.Segment(paragraph) {
-			wordCount := d.NumWords
-			d.NumSentences++
-			words := []Word{}
-			for _, word := range wordTokenizer.Tokenize(s) {
-				word = strings.TrimSpace(word)
-				if len(word) == 0 {
-					continue
-				}
-				d.NumCharacters += countChars(word)
-				if _, found := d.WordFrequency[word]; found {
-					d.WordFrequency[word]++
-				} else {
-					d.WordFrequency[word] = 1
-				}
-				if len(word) > 6 {
-					d.NumLongWords++
-				}
-				syllables := Syllables(word)
-				words = append(words, Word{Text: word, Syllables: syllables})
-				d.NumSyllables += float64(syllables)
-				if syllables > 2 {
-					d.NumPolysylWords++
-				}
-				if isComplex(word, syllables) {
-					d.NumComplexWords++
-				}
-				d.NumWords++
+	if d.WordTokenizer == nil {
+		d.WordTokenizer = wordTokenizer
+	}
+	if d.Segmenter == nil {
+		for i, paragraph := range strings.Split(d.Content, "\n\n") {
+			for _, s := range sentenceTokenizer.Segment(paragraph) {
+				d.addSentence(s, i)
 			}
-			d.Sentences = append(d.Sentences, Sentence{
-				Text:      strings.TrimSpace(s),
-				Length:    int(d.NumWords - wordCount),
-				Words:     words,
-				Paragraph: i})
+			d.NumParagraphs++
+		}
+		return
+	}
+
+	// A Segmenter sees the entire Content, since (e.g., for Markdown) its
+	// blocks may contain blank lines. Each block is a paragraph, as is each
+	// run of sentences without blank lines between them.
+	var block *segment.Block
+	paragraph, end := -1, 0
+	for _, s := range d.Segmenter.Sentences(d.Content) {
+		if paragraph < 0 || (s.Block != nil && s.Block != block) ||
+			(s.Block == nil && strings.Contains(d.Content[end:s.Start], "\n\n")) {
+			paragraph++
+			d.NumParagraphs++
+		}
+		block, end = s.Block, s.End
+		d.addSentence(s.Text, paragraph)
+	}
+}
+
+// addSentence adds the sentence s, which occurs in the given paragraph, to
+// the Document's statistics.
+func (d *Document) addSentence(s string, paragraph int) {
+	wordCount := d.NumWords
+	d.NumSentences++
+	words := []Word{}
+	ignored := ignoredSpans(s)
+	for _, tok := range d.WordTokenizer.Tokens(s) {
+		word := strings.TrimSpace(tok.Text)
+		if len(word) == 0 || isIgnored(tok.Span, ignored) {
+			continue
 		}
-		d.NumParagraphs++
+		d.NumCharacters += countChars(word)
+		if _, found := d.WordFrequency[word]; found {
+			d.WordFrequency[word]++
+		} else {
+			d.WordFrequency[word] = 1
+		}
+		if len(word) > 6 {
+			d.NumLongWords++
+		}
+		syllables := Syllables(word)
+		words = append(words, Word{Text: word, Syllables: syllables})
+		d.NumSyllables += float64(syllables)
+		if syllables > 2 {
+			d.NumPolysylWords++
+		}
+		if isComplex(word, syllables) {
+			d.NumComplexWords++
+		}
+		d.NumWords++
 	}
+	d.Sentences = append(d.Sentences, Sentence{
+		Text:      strings.TrimSpace(s),
+		Length:    int(d.NumWords - wordCount),
+		Words:     words,
+		Paragraph: paragraph})
 }
 
 // Assess returns an Assessment for the Document d.
@@ -168,6 +214,14 @@ func (d *Document) Assess() *Assessment {
 func (d *Document) Summary(n int) []RankedParagraph {
 	rankings := []RankedParagraph{}
 	scores := d.Keywords()
+	if d.Stemmer != nil {
+		// Each keyword's score covers all of the words with its stem.
+		stemmed := make(map[string]int, len(scores))
+		for word, score := range scores {
+			stemmed[d.Stemmer.Stem(word)] = score
+		}
+		scores = stemmed
+	}
 	for i := 0; i < int(d.NumParagraphs); i++ {
 		p := RankedParagraph{Position: i}
 		rank := 0
@@ -176,7 +230,11 @@ func (d *Document) Summary(n int) []RankedParagraph {
 			if s.Paragraph == i {
 				size += s.Length
 				for _, w := range s.Words {
-					if score, found := scores[w.Text]; found {
+					word := w.Text
+					if d.Stemmer != nil {
+						word = d.Stemmer.Stem(word)
+					}
+					if score, found := scores[word]; found {
 						rank += score
 					}
 				}
@@ -214,6 +272,31 @@ func (s byIndex) Len() int           { return len(s) }
 func (s byIndex) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 func (s byIndex) Less(i, j int) bool { return s[i].Position < s[j].Position }
 
+// ignoredSpans returns the spans of the tokens in s whose kinds are ignored.
+func ignoredSpans(s string) []tokenize.Span {
+	var spans []tokenize.Span
+	if !strings.Contains(s, "@") && !strings.Contains(s, "://") && !strings.Contains(s, "www.") {
+		// Neither a URL nor an email address can appear in s.
+		return spans
+	}
+	for _, tok := range entityTokenizer.Tokens(s) {
+		if ignoredKinds[tok.Kind] {
+			spans = append(spans, tok.Span)
+		}
+	}
+	return spans
+}
+
+// isIgnored determines if span overlaps any of the spans in ignored.
+func isIgnored(span tokenize.Span, ignored []tokenize.Span) bool {
+	for _, other := range ignored {
+		if span.Start < other.End && other.Start < span.End {
+			return true
+		}
+	}
+	return false
+}
+
 func isComplex(word string, syllables int) bool {
 	if internal.HasAnySuffix(word, []string{"es", "ed", "ing"}) {
 		syllables--
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/jdkato/twine/internal"
+	"github.com/jdkato/twine/nlp/segment"
+	"github.com/jdkato/twine/nlp/tokenize"
+	"github.com/jdkato/twine/nlp/tokenize/dictionary"
 )
 
 var testdata = filepath.Join("..", "testdata")
@@ -71,3 +75,89 @@ func TestSummarize(t *testing.T) {
 	}
 	fmt.Print(text)
 }
+
+func TestSummarizeIgnoredKinds(t *testing.T) {
+	d := NewDocument("Email jane.doe@example.com or visit https://example.com/docs today.")
+	if d.NumWords != 4 {
+		t.Errorf("Words: got %0.2f; expected %0.2f", d.NumWords, 4.0)
+	}
+	for _, word := range []string{"jane", "example", "https", "docs"} {
+		if _, found := d.WordFrequency[word]; found {
+			t.Errorf("WordFrequency: unexpected word %q", word)
+		}
+	}
+}
+
+func TestSummarizeWordTokenizer(t *testing.T) {
+	d := Document{
+		Content:       "The café serves 3.14 pies to well-known 👩‍💻 guests.",
+		WordTokenizer: tokenize.NewUnicodeWordTokenizer()}
+	d.Initialize()
+
+	if d.NumWords != 10 {
+		t.Errorf("Words: got %0.2f; expected %0.2f", d.NumWords, 10.0)
+	}
+	for _, word := range []string{"café", "3.14", "well", "known", "👩‍💻"} {
+		if _, found := d.WordFrequency[word]; !found {
+			t.Errorf("WordFrequency: missing word %q", word)
+		}
+	}
+}
+
+func TestSummarizeUnspacedScripts(t *testing.T) {
+	d := Document{
+		Content:       "我们在北京大学学习自然语言处理。",
+		WordTokenizer: dictionary.NewTokenizer()}
+	d.Initialize()
+
+	if d.NumWords != 8 {
+		t.Errorf("Words: got %0.2f; expected %0.2f", d.NumWords, 8.0)
+	}
+	if d.WordFrequency["北京"] != 1 {
+		t.Errorf("WordFrequency: missing word %q", "北京")
+	}
+}
+
+func TestSummarizeSegmenter(t *testing.T) {
+	text := "# Features\n- Fast tokenizers\n- Readability scores\n- Summaries"
+
+	plain := NewDocument(text)
+	if plain.NumSentences != 1 {
+		t.Errorf("Sentences: got %0.2f; expected %0.2f", plain.NumSentences, 1.0)
+	}
+
+	d := Document{Content: text, Segmenter: segment.NewMarkdownSegmenter()}
+	d.Initialize()
+
+	if d.NumSentences != 4 {
+		t.Errorf("Sentences: got %0.2f; expected %0.2f", d.NumSentences, 4.0)
+	}
+	if d.NumWords != 6 {
+		t.Errorf("Words: got %0.2f; expected %0.2f", d.NumWords, 6.0)
+	}
+}
+
+func TestSummarizeSegmenterCodeBlock(t *testing.T) {
+	text := "Run the program.\n\n```go\nfunc main() {\n\tfmt.Println(\"x. Y\")\n\n\tos.Exit(1)\n}\n```\n\nIt exits."
+
+	d := Document{Content: text, Segmenter: segment.NewMarkdownSegmenter()}
+	d.Initialize()
+
+	actual := []string{}
+	for _, s := range d.Sentences {
+		actual = append(actual, s.Text)
+	}
+	expected := []string{"Run the program.", "It exits."}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Sentences: got %q; expected %q", actual, expected)
+	}
+	if d.NumParagraphs != 2 || d.Sentences[1].Paragraph != 1 {
+		t.Errorf("Paragraphs: got %0.2f; expected %0.2f", d.NumParagraphs, 2.0)
+	}
+
+	d = Document{Content: "One. Two.\n\nThree.", Segmenter: segment.NewPragmaticSegmenter()}
+	d.Initialize()
+	if d.NumParagraphs != 2 || d.Sentences[2].Paragraph != 1 {
+		t.Errorf("Paragraphs: got %0.2f; expected %0.2f", d.NumParagraphs, 2.0)
+	}
+}
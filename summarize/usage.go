@@ -3,6 +3,8 @@ package summarize
 import (
 	"strings"
 
+	"github.com/jdkato/twine/nlp/collocation"
+	"github.com/jdkato/twine/nlp/ngram"
 	"github.com/montanaflynn/stats"
 )
 
@@ -21,6 +23,10 @@ func (d *Document) WordDensity() map[string]float64 {
 //    map[word]count
 //
 // omitting stop words and normalizing case.
+//
+// If the Document has a Stemmer, words with the same stem are counted
+// together under their most frequent form (e.g., "run", "runs" and "running"
+// are all counted as "running" if it occurs most often).
 func (d *Document) Keywords() map[string]int {
 	scores := map[string]int{}
 	for word, freq := range d.WordFrequency {
@@ -34,7 +40,91 @@ func (d *Document) Keywords() map[string]int {
 			scores[normalized] = freq
 		}
 	}
-	return scores
+	if d.Stemmer == nil {
+		return scores
+	}
+
+	totals := map[string]int{}
+	forms := map[string]string{}
+	for word, freq := range scores {
+		stem := d.Stemmer.Stem(word)
+		totals[stem] += freq
+		// Ties are broken alphabetically, so that the result doesn't depend
+		// on the map's iteration order.
+		form, found := forms[stem]
+		if !found || freq > scores[form] || (freq == scores[form] && word < form) {
+			forms[stem] = word
+		}
+	}
+
+	keywords := make(map[string]int, len(forms))
+	for stem, form := range forms {
+		keywords[form] = totals[stem]
+	}
+	return keywords
+}
+
+// StemFrequency returns a Document's words in the form
+//
+//    map[stem]frequency
+//
+// grouping them with the Document's Stemmer. If the Document doesn't have a
+// Stemmer, words are only grouped by case.
+func (d *Document) StemFrequency() map[string]int {
+	frequency := map[string]int{}
+	for word, freq := range d.WordFrequency {
+		frequency[d.stem(word)] += freq
+	}
+	return frequency
+}
+
+// stem returns the stem of word according to the Document's Stemmer, or word
+// in lowercase if it doesn't have one.
+func (d *Document) stem(word string) string {
+	if d.Stemmer == nil {
+		return strings.ToLower(word)
+	}
+	return d.Stemmer.Stem(word)
+}
+
+// TopNGrams returns a Document's k most frequent word n-grams, in descending
+// order of frequency, normalizing case. N-grams don't span sentences.
+//
+// Stop-word boundaries (or any others) can be set with opts:
+//
+//    d.TopNGrams(2, 10, ngram.UsingBoundary(summarize.IsStopWord))
+func (d *Document) TopNGrams(n, k int, opts ...ngram.GeneratorOptFunc) []ngram.Frequency {
+	generator := ngram.NewGenerator(opts...)
+	counter := ngram.NewCounter()
+	for _, s := range d.Sentences {
+		counter.Add(generator.NGrams(s.lowerWords(), n)...)
+	}
+	return counter.Top(k)
+}
+
+// BigramCollocations returns a Document's two-word collocations ranked by
+// measure, normalizing case.
+//
+// For example, to find the terms that occur at least three times:
+//
+//    d.BigramCollocations(collocation.LikelihoodRatio,
+//        collocation.UsingMinFrequency(3),
+//        collocation.UsingWordFilter(summarize.IsStopWord))
+func (d *Document) BigramCollocations(measure collocation.Measure, opts ...collocation.FinderOptFunc) []collocation.Collocation {
+	return d.collocations(collocation.NewBigramFinder(opts...), measure)
+}
+
+// TrigramCollocations returns a Document's three-word collocations ranked by
+// measure, normalizing case.
+func (d *Document) TrigramCollocations(measure collocation.Measure, opts ...collocation.FinderOptFunc) []collocation.Collocation {
+	return d.collocations(collocation.NewTrigramFinder(opts...), measure)
+}
+
+func (d *Document) collocations(f *collocation.Finder, measure collocation.Measure) []collocation.Collocation {
+	for _, s := range d.Sentences {
+		f.Add(s.lowerWords())
+	}
+	return f.Score(measure)
 }
 
 // MeanWordLength returns the mean number of characters per word.
@@ -42,3 +132,12 @@ func (d *Document) MeanWordLength() float64 {
 	val, _ := stats.Round(d.NumCharacters/d.NumWords, 3)
 	return val
 }
+
+// lowerWords returns the text of a Sentence's words in lowercase.
+func (s Sentence) lowerWords() []string {
+	words := make([]string, len(s.Words))
+	for i, word := range s.Words {
+		words[i] = strings.ToLower(word.Text)
+	}
+	return words
+}
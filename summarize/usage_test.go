@@ -3,6 +3,10 @@ package summarize
 import (
 	"reflect"
 	"testing"
+
+	"github.com/jdkato/twine/nlp/collocation"
+	"github.com/jdkato/twine/nlp/ngram"
+	"github.com/jdkato/twine/nlp/stem"
 )
 
 var dmap = map[string]float64{
@@ -27,3 +31,73 @@ func TestUsage(t *testing.T) {
 		t.Errorf("MeanWordLength: got %f; expected %f", d.MeanWordLength(), 5.163)
 	}
 }
+
+func TestKeywordsStemmed(t *testing.T) {
+	text := "Running is fun. She runs every day, and he ran once. Runners love running."
+	d := Document{Content: text, Stemmer: stem.NewEnglishStemmer()}
+	d.Initialize()
+
+	expected := map[string]int{
+		"running": 3, "fun": 1, "day": 1, "ran": 1, "runners": 1, "love": 1}
+	if keywords := d.Keywords(); !reflect.DeepEqual(expected, keywords) {
+		t.Errorf("Keywords: got %v; expected %v", keywords, expected)
+	}
+
+	frequency := d.StemFrequency()
+	if frequency["run"] != 3 || frequency["runner"] != 1 || frequency["she"] != 1 {
+		t.Errorf("StemFrequency: got %v", frequency)
+	}
+
+	d = Document{Content: text}
+	d.Initialize()
+	if frequency := d.StemFrequency(); frequency["running"] != 2 || frequency["runs"] != 1 {
+		t.Errorf("StemFrequency: got %v", frequency)
+	}
+}
+
+func TestTopNGrams(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog. The lazy dog sleeps. A quick brown fox naps."
+	d := NewDocument(text)
+
+	top := d.TopNGrams(2, 2)
+	if len(top) != 2 {
+		t.Fatalf("TopNGrams: got %v; expected 2 n-grams", top)
+	}
+	for i, expected := range []string{"brown fox", "lazy dog"} {
+		if top[i].NGram.String() != expected || top[i].Count != 2 {
+			t.Errorf("TopNGrams: got %v; expected '%s' (2)", top[i], expected)
+		}
+	}
+
+	for _, f := range d.TopNGrams(2, -1, ngram.UsingBoundary(IsStopWord)) {
+		for _, word := range f.NGram {
+			if IsStopWord(word) {
+				t.Errorf("TopNGrams: '%s' contains a stop word", f.NGram)
+			}
+		}
+		if f.NGram.String() == "dog the" {
+			t.Errorf("TopNGrams: '%s' spans sentences", f.NGram)
+		}
+	}
+}
+
+func TestCollocations(t *testing.T) {
+	text := "Open a pull request to merge your branch. The pull request was merged. Create a new access token. Revoke the access token now. The token was new."
+	d := NewDocument(text)
+
+	top := d.BigramCollocations(collocation.LikelihoodRatio,
+		collocation.UsingMinFrequency(2),
+		collocation.UsingWordFilter(IsStopWord))
+	if len(top) != 2 || top[0].String() != "pull request" || top[1].String() != "access token" {
+		t.Errorf("BigramCollocations: got %v; expected [pull request access token]", top)
+	}
+
+	for _, c := range d.TrigramCollocations(collocation.StudentT) {
+		if c.String() == "merge your branch" && c.Count != 1 {
+			t.Errorf("TrigramCollocations: got %d for '%s'; expected 1", c.Count, c)
+		}
+		if c.String() == "branch the pull" {
+			t.Errorf("TrigramCollocations: '%s' spans sentences", c)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+"""Generates treebank_words_nltk.json, the output of NLTK's
+TreebankWordTokenizer for each sentence in treebank_sents.json:
+
+    pip install nltk==3.8.1
+    python3 treebank_nltk.py > treebank_words_nltk.json
+
+If NLTK isn't installed, the sentences are tokenized with the rules below,
+which are copied from nltk/tokenize/treebank.py in NLTK 3.8.1; with NLTK
+installed, the script also checks that the two agree.
+"""
+import json
+import os
+import re
+import sys
+
+NLTK_VERSION = "3.8.1"
+
+STARTING_QUOTES = [
+    (re.compile(r"^\""), r"``"),
+    (re.compile(r"(``)"), r" \1 "),
+    (re.compile(r"([ \(\[{<])(\"|\'{2})"), r"\1 `` "),
+]
+
+PUNCTUATION = [
+    (re.compile(r"([:,])([^\d])"), r" \1 \2"),
+    (re.compile(r"([:,])$"), r" \1 "),
+    (re.compile(r"\.\.\."), r" ... "),
+    (re.compile(r"[;@#$%&]"), r" \g<0> "),
+    (re.compile(r'([^\.])(\.)([\]\)}>"\']*)\s*$'), r"\1 \2\3 "),
+    (re.compile(r"[?!]"), r" \g<0> "),
+    (re.compile(r"([^'])' "), r"\1 ' "),
+]
+
+PARENS_BRACKETS = (re.compile(r"[\]\[\(\)\{\}\<\>]"), r" \g<0> ")
+
+DOUBLE_DASHES = (re.compile(r"--"), r" -- ")
+
+ENDING_QUOTES = [
+    (re.compile(r"''"), " '' "),
+    (re.compile(r'"'), " '' "),
+    (re.compile(r"([^' ])('[sS]|'[mM]|'[dD]|') "), r"\1 \2 "),
+    (re.compile(r"([^' ])('ll|'LL|'re|'RE|'ve|'VE|n't|N'T) "), r"\1 \2 "),
+]
+
+CONTRACTIONS2 = [
+    re.compile(r"(?i)\b(can)(?#X)(not)\b"),
+    re.compile(r"(?i)\b(d)(?#X)('ye)\b"),
+    re.compile(r"(?i)\b(gim)(?#X)(me)\b"),
+    re.compile(r"(?i)\b(gon)(?#X)(na)\b"),
+    re.compile(r"(?i)\b(got)(?#X)(ta)\b"),
+    re.compile(r"(?i)\b(lem)(?#X)(me)\b"),
+    re.compile(r"(?i)\b(mor)(?#X)('n)\b"),
+    re.compile(r"(?i)\b(wan)(?#X)(na)(?=\s)"),
+]
+
+CONTRACTIONS3 = [
+    re.compile(r"(?i) ('t)(?#X)(is)\b"),
+    re.compile(r"(?i) ('t)(?#X)(was)\b"),
+]
+
+
+def tokenize(text):
+    for regexp, substitution in STARTING_QUOTES:
+        text = regexp.sub(substitution, text)
+    for regexp, substitution in PUNCTUATION:
+        text = regexp.sub(substitution, text)
+    regexp, substitution = PARENS_BRACKETS
+    text = regexp.sub(substitution, text)
+    regexp, substitution = DOUBLE_DASHES
+    text = regexp.sub(substitution, text)
+    text = " " + text + " "
+    for regexp, substitution in ENDING_QUOTES:
+        text = regexp.sub(substitution, text)
+    for regexp in CONTRACTIONS2:
+        text = regexp.sub(r" \1 \2 ", text)
+    for regexp in CONTRACTIONS3:
+        text = regexp.sub(r" \1 \2 ", text)
+    return text.split()
+
+
+def main():
+    path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "treebank_sents.json")
+    with open(path, encoding="utf-8") as f:
+        sents = json.load(f)
+
+    words = [tokenize(s) for s in sents]
+    try:
+        import nltk
+        from nltk.tokenize import TreebankWordTokenizer
+    except ImportError:
+        print("NLTK isn't installed; using the copied rules", file=sys.stderr)
+    else:
+        if nltk.__version__ != NLTK_VERSION:
+            sys.exit("expected NLTK %s, got %s" % (NLTK_VERSION, nltk.__version__))
+        tokenizer = TreebankWordTokenizer()
+        for s, expected in zip(sents, words):
+            if tokenizer.tokenize(s) != expected:
+                sys.exit("the copied rules disagree with NLTK on %r" % s)
+
+    json.dump(words, sys.stdout, indent=4)
+    print()
+
+
+if __name__ == "__main__":
+    main()